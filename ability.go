@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jamistoso/pokedexcli/internal/pokeapi"
+)
+
+type ability struct {
+	ID            int           `json:"id"`
+	Name          string        `json:"name"`
+	EffectEntries []effectEntry `json:"effect_entries"`
+	Pokemon       []struct {
+		IsHidden bool                  `json:"is_hidden"`
+		Pokemon  pokeapi.NamedResource `json:"pokemon"`
+	} `json:"pokemon"`
+}
+
+func fetchAbility(conf *config, name string) (ability, error) {
+	data, err := fetch(conf, abilityURL+name)
+	if err != nil {
+		return ability{}, err
+	}
+	var a ability
+	if err := json.Unmarshal(data, &a); err != nil {
+		return ability{}, fmt.Errorf("ability retrieval failed: %s", err)
+	}
+	return a, nil
+}
+
+func commandAbility(conf *config, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: ability <name>")
+	}
+	a, err := fetchAbility(conf, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println("Name: " + a.Name)
+	if effect := effectText(a.EffectEntries, conf.language); effect != "" {
+		fmt.Println(effect)
+	}
+	holders := []string{}
+	for _, p := range a.Pokemon {
+		name := p.Pokemon.Name
+		if p.IsHidden {
+			name += " (hidden)"
+		}
+		holders = append(holders, name)
+	}
+	if len(holders) > 0 {
+		fmt.Println("Pokemon: " + strings.Join(holders, ", "))
+	}
+	return nil
+}
+
+// abilityLines lists a pokemon's abilities in slot order, marking the
+// hidden one.
+func abilityLines(pokemon Pokemon) []string {
+	lines := []string{}
+	for _, a := range pokemon.Abilities {
+		line := "	- " + a.Ability.Name
+		if a.IsHidden {
+			line += " (hidden)"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func printAbilities(pokemon Pokemon) {
+	fmt.Println("Abilities:")
+	for _, line := range abilityLines(pokemon) {
+		fmt.Println(line)
+	}
+}
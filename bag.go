@@ -0,0 +1,315 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bagPockets lists the bag's pockets in display order.
+var bagPockets = []struct {
+	name  string
+	title string
+}{
+	{"balls", "Balls"},
+	{"medicine", "Medicine"},
+	{"berries", "Berries"},
+	{"evolution", "Evolution items"},
+	{"tms", "TMs"},
+	{"items", "Other items"},
+}
+
+type bagItem struct {
+	pocket string
+	count  int
+}
+
+// healAmounts is the HP restored by healing items; 0 restores all of it.
+var healAmounts = map[string]int{
+	"potion":       20,
+	"super-potion": 50,
+	"hyper-potion": 200,
+	"max-potion":   0,
+	"full-restore": 0,
+	"fresh-water":  50,
+	"soda-pop":     60,
+	"lemonade":     80,
+	"moomoo-milk":  100,
+	"oran-berry":   10,
+}
+
+// vitamins maps each vitamin to the stat whose effort values it raises.
+var vitamins = map[string]string{
+	"hp-up":   "hp",
+	"protein": "attack",
+	"iron":    "defense",
+	"calcium": "special-attack",
+	"zinc":    "special-defense",
+	"carbos":  "speed",
+}
+
+const vitaminEVs = 10
+
+func (t *trainer) addItem(name string, pocket string, n int) {
+	if entry, ok := t.bag[name]; ok {
+		entry.count += n
+		return
+	}
+	t.bag[name] = &bagItem{pocket: pocket, count: n}
+}
+
+func (t *trainer) takeItem(name string) error {
+	entry, ok := t.bag[name]
+	if !ok || entry.count <= 0 {
+		return fmt.Errorf("you don't have any %s", name)
+	}
+	entry.count--
+	if entry.count == 0 {
+		delete(t.bag, name)
+	}
+	return nil
+}
+
+func (t *trainer) count(name string) int {
+	if entry, ok := t.bag[name]; ok {
+		return entry.count
+	}
+	return 0
+}
+
+// itemPocket picks the bag pocket for an item from its category.
+func itemPocket(it item) string {
+	if strings.HasSuffix(it.Name, "-berry") {
+		return "berries"
+	}
+	switch it.Category.Name {
+	case "standard-balls", "special-balls", "apricorn-balls":
+		return "balls"
+	case "healing", "status-cures", "revival", "pp-recovery", "vitamins":
+		return "medicine"
+	case "effort-drop", "in-a-pinch", "picky-healing", "type-protection", "baking-only":
+		return "berries"
+	case "evolution":
+		return "evolution"
+	case "all-machines":
+		return "tms"
+	default:
+		return "items"
+	}
+}
+
+// pickUpItem puts one of the named item in the bag, looking it up to find
+// its pocket.
+func pickUpItem(conf *config, name string) error {
+	it, err := fetchItem(conf, name)
+	if err != nil {
+		return err
+	}
+	conf.trainer.addItem(it.Name, itemPocket(it), 1)
+	return nil
+}
+
+// rollHeldItem decides whether a wild pokemon was holding one of its items,
+// using the rarities listed for the selected version. Without a version the
+// first rarity listed for each item is used.
+func rollHeldItem(rng *rand.Rand, pokemon Pokemon, version string) (string, bool) {
+	for _, held := range pokemon.HeldItems {
+		for _, detail := range held.VersionDetails {
+			if !inVersion(version, detail.Version.Name) {
+				continue
+			}
+			if rng.Intn(100) < detail.Rarity {
+				return held.Item.Name, true
+			}
+			break
+		}
+	}
+	return "", false
+}
+
+func commandBag(conf *config, args ...string) error {
+	pocket := ""
+	if len(args) > 0 {
+		pocket = args[0]
+		known := false
+		for _, p := range bagPockets {
+			known = known || p.name == pocket
+		}
+		if !known {
+			return fmt.Errorf("unknown pocket: %s", pocket)
+		}
+	}
+
+	empty := true
+	for _, p := range bagPockets {
+		if pocket != "" && p.name != pocket {
+			continue
+		}
+		names := []string{}
+		for name, entry := range conf.trainer.bag {
+			if entry.pocket == p.name {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+		sort.Strings(names)
+		empty = false
+		fmt.Println(p.title + ":")
+		for _, name := range names {
+			fmt.Println("	- " + name + " x" + strconv.Itoa(conf.trainer.bag[name].count))
+		}
+	}
+	if empty {
+		fmt.Println("Your bag is empty")
+	}
+	return nil
+}
+
+func commandUse(conf *config, args ...string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: use <item> <id|name>")
+	}
+	name := args[0]
+	entry, ok := conf.trainer.bag[name]
+	if !ok {
+		return fmt.Errorf("you don't have any %s", name)
+	}
+	caught, err := findCaught(conf, args[1])
+	if err != nil {
+		return err
+	}
+	used, err := useItem(conf, caught, name, entry.pocket)
+	if err != nil {
+		return err
+	}
+	if !used {
+		fmt.Println("It won't have any effect.")
+		return nil
+	}
+	return conf.trainer.takeItem(name)
+}
+
+// useItem applies an item to a caught pokemon and reports whether it did
+// anything, in which case the item is used up.
+func useItem(conf *config, caught *caughtPokemon, name string, pocket string) (bool, error) {
+	if amount, ok := healAmounts[name]; ok {
+		b := conf.battle
+		if b == nil || b.caught != caught {
+			return false, nil
+		}
+		healed := heal(b.player, amount)
+		if healed == 0 {
+			return false, nil
+		}
+		fmt.Println(b.player.name + " recovered " + strconv.Itoa(healed) + " HP (" + strconv.Itoa(b.player.hp) + "/" + strconv.Itoa(b.player.stats["hp"]) + " HP)")
+		return true, nil
+	}
+	if stat, ok := vitamins[name]; ok {
+		gained := caught.addEffort(stat, vitaminEVs)
+		if gained == 0 {
+			return false, nil
+		}
+		fmt.Println(caught.displayName() + "'s " + stat + " effort values rose by " + strconv.Itoa(gained))
+		return true, nil
+	}
+	if name == "rare-candy" {
+		if caught.Level >= maxLevel {
+			return false, nil
+		}
+		for _, level := range caught.gainExperience(experienceForLevel(caught.GrowthRate, caught.Level+1) - caught.Experience) {
+			fmt.Println(caught.displayName() + " grew to level " + strconv.Itoa(level) + "!")
+		}
+		return true, nil
+	}
+	switch pocket {
+	case "evolution":
+		return useEvolutionItem(conf, caught, name)
+	case "tms":
+		return useMachine(conf, caught, name)
+	}
+	return false, nil
+}
+
+// useMachine teaches the move of a TM or HM, following the same rules as
+// the teach command.
+func useMachine(conf *config, caught *caughtPokemon, name string) (bool, error) {
+	it, err := fetchItem(conf, name)
+	if err != nil {
+		return false, err
+	}
+	moveName, err := machineMove(conf, it, conf.versionGroup)
+	if err != nil {
+		return false, err
+	}
+	if err := learnMove(caught, conf.versionGroup, moveName); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// useEvolutionItem evolves the pokemon if one of its evolutions is
+// triggered by the item.
+func useEvolutionItem(conf *config, caught *caughtPokemon, name string) (bool, error) {
+	link, err := evolutionLink(conf, caught)
+	if err != nil {
+		return false, err
+	}
+	target := ""
+	for _, next := range link.EvolvesTo {
+		for _, detail := range next.EvolutionDetails {
+			if detail.Trigger.Name == "use-item" && detail.Item != nil && detail.Item.Name == name {
+				target = next.Species.Name
+			}
+		}
+	}
+	if target == "" {
+		return false, nil
+	}
+	next, _, err := evolutionTarget(link, caught, target, evolveContext{now: time.Now(), usedItem: name})
+	if err != nil {
+		return false, err
+	}
+	return true, evolveInto(conf, caught, next.Species.Name)
+}
+
+// heal restores up to amount HP, or all of it when amount is 0, and returns
+// how much was restored.
+func heal(c *combatant, amount int) int {
+	missing := c.stats["hp"] - c.hp
+	if amount == 0 || amount > missing {
+		amount = missing
+	}
+	c.hp += amount
+	return amount
+}
+
+func commandGive(conf *config, args ...string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: give <item> <id|name>")
+	}
+	name := args[0]
+	if conf.trainer.count(name) == 0 {
+		return fmt.Errorf("you don't have any %s", name)
+	}
+	caught, err := findCaught(conf, args[1])
+	if err != nil {
+		return err
+	}
+	if previous := caught.HeldItem; previous != "" {
+		if err := pickUpItem(conf, previous); err != nil {
+			return err
+		}
+		fmt.Println("Took the " + previous + " from " + caught.displayName() + " and put it in the bag")
+	}
+	if err := conf.trainer.takeItem(name); err != nil {
+		return err
+	}
+	caught.HeldItem = name
+	fmt.Println(caught.displayName() + " is now holding a " + name)
+	return nil
+}
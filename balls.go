@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ballContext is what a ball needs to know about the throw to pick its
+// catch modifier.
+type ballContext struct {
+	types []string
+	now   time.Time
+}
+
+type pokeBall struct {
+	name        string
+	description string
+	modifier    func(ctx ballContext) float64
+}
+
+func flatModifier(m float64) func(ballContext) float64 {
+	return func(ballContext) float64 { return m }
+}
+
+var pokeBalls = map[string]pokeBall{
+	"poke": {
+		name:        "Poke Ball",
+		description: "A standard ball",
+		modifier:    flatModifier(1),
+	},
+	"great": {
+		name:        "Great Ball",
+		description: "1.5x catch rate",
+		modifier:    flatModifier(1.5),
+	},
+	"ultra": {
+		name:        "Ultra Ball",
+		description: "2x catch rate",
+		modifier:    flatModifier(2),
+	},
+	"master": {
+		name:        "Master Ball",
+		description: "Never fails",
+		modifier:    flatModifier(255),
+	},
+	"net": {
+		name:        "Net Ball",
+		description: "3.5x against water and bug types",
+		modifier: func(ctx ballContext) float64 {
+			if slices.Contains(ctx.types, "water") || slices.Contains(ctx.types, "bug") {
+				return 3.5
+			}
+			return 1
+		},
+	},
+	"dusk": {
+		name:        "Dusk Ball",
+		description: "3x at night (18:00-05:59)",
+		modifier: func(ctx ballContext) float64 {
+			hour := ctx.now.Hour()
+			if hour >= 18 || hour < 6 {
+				return 3
+			}
+			return 1
+		},
+	},
+}
+
+type trainer struct {
+	bag   map[string]*bagItem
+	party []int
+	boxes []box
+}
+
+func newTrainer() *trainer {
+	t := &trainer{bag: map[string]*bagItem{}}
+	for kind, n := range map[string]int{
+		"poke":   20,
+		"great":  10,
+		"ultra":  5,
+		"master": 1,
+		"net":    5,
+		"dusk":   5,
+	} {
+		t.addItem(ballItem(kind), "balls", n)
+	}
+	t.addItem("potion", "medicine", 5)
+	return t
+}
+
+// ballItem is the item name a ball kind is kept under in the bag.
+func ballItem(kind string) string {
+	return kind + "-ball"
+}
+
+// takeBall removes one ball of the given kind from the inventory.
+func (t *trainer) takeBall(kind string) (pokeBall, error) {
+	ball, ok := pokeBalls[kind]
+	if !ok {
+		return pokeBall{}, fmt.Errorf("unknown ball: %s", kind)
+	}
+	if err := t.takeItem(ballItem(kind)); err != nil {
+		return pokeBall{}, fmt.Errorf("you are out of %ss", ball.name)
+	}
+	return ball, nil
+}
+
+func pokemonTypes(pokemon Pokemon) []string {
+	types := []string{}
+	for _, pokeType := range pokemon.Types {
+		types = append(types, pokeType.Type.Name)
+	}
+	return types
+}
+
+func commandBalls(conf *config, args ...string) error {
+	kinds := []string{}
+	for kind := range pokeBalls {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	fmt.Println("Your balls:")
+	for _, kind := range kinds {
+		ball := pokeBalls[kind]
+		fmt.Println(" - " + kind + ": " + strconv.Itoa(conf.trainer.count(ballItem(kind))) + " " + ball.name + " (" + ball.description + ")")
+	}
+	return nil
+}
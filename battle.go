@@ -0,0 +1,275 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jamistoso/pokedexcli/internal/pokeapi"
+)
+
+// combatant is one side of a battle with its stats at the current level.
+type combatant struct {
+	name  string
+	level int
+	types []string
+	stats map[string]int
+	hp    int
+	moves []move
+}
+
+type battle struct {
+	player      *combatant
+	wild        *combatant
+	caught      *caughtPokemon
+	wildPokemon Pokemon
+	encounter   *wildEncounter
+}
+
+// struggle is used when a pokemon knows no damaging move. It has no type,
+// so it gets neither STAB nor type effectiveness.
+var struggle = move{Name: "struggle", Power: intPtr(50), DamageClass: pokeapi.NamedResource{Name: "physical"}}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+// computeStats applies the mainline stat formulas to base stats, without
+// individual values, effort values or nature.
+func computeStats(base map[string]int, level int) map[string]int {
+	return calcStats(base, nil, nil, "", level)
+}
+
+func newCombatant(pokemon Pokemon, name string, level int, stats map[string]int, moves []move) *combatant {
+	return &combatant{
+		name:  name,
+		level: level,
+		types: pokemonTypes(pokemon),
+		stats: stats,
+		hp:    stats["hp"],
+		moves: moves,
+	}
+}
+
+// usableMoves drops moves that can't deal damage, falling back to struggle.
+func usableMoves(moves []move) []move {
+	usable := []move{}
+	for _, m := range moves {
+		if m.isDamaging() {
+			usable = append(usable, m)
+		}
+	}
+	if len(usable) == 0 {
+		usable = append(usable, struggle)
+	}
+	return usable
+}
+
+type attackResult struct {
+	hit           bool
+	damage        int
+	crit          bool
+	effectiveness float64
+}
+
+// calcDamage resolves one attack with the generation VI+ damage formula:
+// accuracy check, 1/24 critical hits at 1.5x, STAB, type effectiveness and
+// a random factor between 0.85 and 1.
+func calcDamage(rng *rand.Rand, chart typeChart, attacker, defender *combatant, m move) attackResult {
+	if m.Accuracy != nil && rng.Intn(100) >= *m.Accuracy {
+		return attackResult{}
+	}
+	if !m.isDamaging() {
+		return attackResult{hit: true, effectiveness: 1}
+	}
+
+	attack, defense := attacker.stats["attack"], defender.stats["defense"]
+	if m.DamageClass.Name == "special" {
+		attack, defense = attacker.stats["special-attack"], defender.stats["special-defense"]
+	}
+	base := float64((2*attacker.level/5+2)*(*m.Power)*attack/max(defense, 1))/50 + 2
+
+	result := attackResult{hit: true, effectiveness: 1}
+	if m.Type.Name != "" {
+		result.effectiveness = chart.multiplier(m.Type.Name, defender.types)
+	}
+	modifier := result.effectiveness * (85 + float64(rng.Intn(16))) / 100
+	if m.Type.Name != "" && slices.Contains(attacker.types, m.Type.Name) {
+		modifier *= 1.5
+	}
+	if rng.Intn(24) == 0 {
+		result.crit = true
+		modifier *= 1.5
+	}
+	result.damage = int(base * modifier)
+	if result.effectiveness > 0 && result.damage < 1 {
+		result.damage = 1
+	}
+	return result
+}
+
+// attack runs one attack and returns what happened.
+func attack(rng *rand.Rand, chart typeChart, attacker, defender *combatant, m move) []string {
+	log := []string{attacker.name + " used " + m.Name + "!"}
+	result := calcDamage(rng, chart, attacker, defender, m)
+	if !result.hit {
+		return append(log, "It missed!")
+	}
+	if !m.isDamaging() {
+		return append(log, "But nothing happened!")
+	}
+	if result.crit {
+		log = append(log, "A critical hit!")
+	}
+	switch {
+	case result.effectiveness == 0:
+		return append(log, "It doesn't affect "+defender.name+"...")
+	case result.effectiveness > 1:
+		log = append(log, "It's super effective!")
+	case result.effectiveness < 1:
+		log = append(log, "It's not very effective...")
+	}
+	defender.hp = max(defender.hp-result.damage, 0)
+	return append(log, defender.name+" took "+strconv.Itoa(result.damage)+" damage ("+strconv.Itoa(defender.hp)+"/"+strconv.Itoa(defender.stats["hp"])+" HP)")
+}
+
+// playerFirst decides turn order: higher move priority, then higher speed,
+// then a coin flip.
+func playerFirst(rng *rand.Rand, player, wild *combatant, playerMove, wildMove move) bool {
+	if playerMove.Priority != wildMove.Priority {
+		return playerMove.Priority > wildMove.Priority
+	}
+	if player.stats["speed"] != wild.stats["speed"] {
+		return player.stats["speed"] > wild.stats["speed"]
+	}
+	return rng.Intn(2) == 0
+}
+
+// turn plays one round: both sides attack in speed order, and the second
+// attack is skipped if the first one knocked the target out.
+func (b *battle) turn(rng *rand.Rand, chart typeChart, playerMove move) []string {
+	wildMove := b.wild.moves[rng.Intn(len(b.wild.moves))]
+	first, second := b.player, b.wild
+	firstMove, secondMove := playerMove, wildMove
+	if !playerFirst(rng, b.player, b.wild, playerMove, wildMove) {
+		first, second = second, first
+		firstMove, secondMove = secondMove, firstMove
+	}
+
+	log := attack(rng, chart, first, second, firstMove)
+	if second.hp > 0 {
+		log = append(log, attack(rng, chart, second, first, secondMove)...)
+	}
+	b.encounter.hp = b.wild.hp
+	return log
+}
+
+func (b *battle) over() bool {
+	return b.player.hp == 0 || b.wild.hp == 0
+}
+
+func commandBattle(conf *config, args ...string) error {
+	if conf.encounter == nil {
+		return fmt.Errorf("there is no wild pokemon to battle, try walk, surf or fish")
+	}
+	if conf.battle != nil {
+		return fmt.Errorf("you are already battling %s", conf.battle.wild.name)
+	}
+	if len(conf.trainer.party) == 0 {
+		return fmt.Errorf("you have no pokemon in your party")
+	}
+	lead := conf.pokedex[conf.trainer.party[0]]
+
+	wildPokemon, err := fetchPokemon(conf, conf.encounter.pokemon)
+	if err != nil {
+		return err
+	}
+	wildMoves, err := fetchMoves(conf, defaultMoveset(wildPokemon, conf.versionGroup, conf.encounter.level))
+	if err != nil {
+		return err
+	}
+	playerMoves, err := fetchMoves(conf, knownMoves(lead, conf.versionGroup))
+	if err != nil {
+		return err
+	}
+	if _, err := loadTypeChart(conf); err != nil {
+		return err
+	}
+
+	wildStats := computeStats(baseStats(wildPokemon), conf.encounter.level)
+	b := &battle{
+		player:      newCombatant(lead.Pokemon, lead.displayName(), lead.Level, lead.stats(), usableMoves(playerMoves)),
+		wild:        newCombatant(wildPokemon, "wild "+wildPokemon.Name, conf.encounter.level, wildStats, usableMoves(wildMoves)),
+		caught:      lead,
+		wildPokemon: wildPokemon,
+		encounter:   conf.encounter,
+	}
+	if conf.encounter.maxHP > 0 {
+		b.wild.hp = conf.encounter.hp
+	}
+	conf.encounter.maxHP = b.wild.stats["hp"]
+	conf.encounter.hp = b.wild.hp
+	conf.battle = b
+
+	fmt.Println("Go, " + b.player.name + "! (Lv. " + strconv.Itoa(b.player.level) + ", " + strconv.Itoa(b.player.hp) + " HP)")
+	printMoveChoices(b.player)
+	return nil
+}
+
+func printMoveChoices(c *combatant) {
+	names := []string{}
+	for _, m := range c.moves {
+		names = append(names, m.Name)
+	}
+	fmt.Println("Moves: " + strings.Join(names, ", "))
+}
+
+func commandFight(conf *config, args ...string) error {
+	b := conf.battle
+	if b == nil {
+		return fmt.Errorf("you are not in a battle")
+	}
+	if len(args) == 0 {
+		printMoveChoices(b.player)
+		return fmt.Errorf("usage: fight <move>")
+	}
+	idx := slices.IndexFunc(b.player.moves, func(m move) bool { return m.Name == args[0] })
+	if idx < 0 {
+		printMoveChoices(b.player)
+		return fmt.Errorf("%s doesn't know %s", b.player.name, args[0])
+	}
+
+	for _, line := range b.turn(conf.rng, conf.typeChart, b.player.moves[idx]) {
+		fmt.Println(line)
+	}
+	if !b.over() {
+		return nil
+	}
+	if b.wild.hp == 0 {
+		fmt.Println(strings.ToUpper(b.wild.name[:1]) + b.wild.name[1:] + " fainted!")
+		xp := battleExperience(b.wildPokemon, b.wild.level)
+		b.caught.gainEffort(b.wildPokemon)
+		fmt.Println(b.player.name + " gained " + strconv.Itoa(xp) + " experience points!")
+		for _, level := range b.caught.gainExperience(xp) {
+			fmt.Println(b.player.name + " grew to level " + strconv.Itoa(level) + "!")
+		}
+		setEncounter(conf, nil)
+	} else {
+		fmt.Println(b.player.name + " fainted! You ran back to safety")
+		setEncounter(conf, nil)
+	}
+	return nil
+}
+
+func commandRun(conf *config, args ...string) error {
+	if conf.battle == nil {
+		return fmt.Errorf("you are not in a battle")
+	}
+	// The wild pokemon stays where it is, keeping any damage it took for a
+	// later battle or catch attempt.
+	conf.battle = nil
+	fmt.Println("Got away safely! The " + conf.encounter.pokemon + " is still nearby (" + strconv.Itoa(conf.encounter.hp) + "/" + strconv.Itoa(conf.encounter.maxHP) + " HP)")
+	return nil
+}
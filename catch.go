@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// catchInput holds the values that feed the capture formula. HP is only
+// relevant once the target has been weakened; a fresh encounter has
+// currentHP equal to maxHP.
+type catchInput struct {
+	captureRate int
+	maxHP       int
+	currentHP   int
+	ballBonus   float64
+	statusBonus float64
+}
+
+// attemptCatch runs the generation III/IV capture formula. It returns the
+// number of shake checks passed (0-4) and whether the pokemon was caught,
+// which only happens when all four checks pass.
+func attemptCatch(rng *rand.Rand, in catchInput) (int, bool) {
+	if in.maxHP <= 0 {
+		in.maxHP = 1
+		in.currentHP = 1
+	}
+	if in.ballBonus == 0 {
+		in.ballBonus = 1
+	}
+	if in.statusBonus == 0 {
+		in.statusBonus = 1
+	}
+
+	hpFactor := float64(3*in.maxHP-2*in.currentHP) / float64(3*in.maxHP)
+	a := hpFactor * float64(in.captureRate) * in.ballBonus * in.statusBonus
+	if a >= 255 {
+		return 4, true
+	}
+	if a < 1 {
+		a = 1
+	}
+
+	b := 1048560 / math.Sqrt(math.Sqrt(16711680/a))
+	shakes := 0
+	for shakes < 4 && float64(rng.Intn(65536)) < b {
+		shakes++
+	}
+	return shakes, shakes == 4
+}
+
+func commandCatch(conf *config, args ...string) error {
+	positional, flags := parseFlags(args)
+	kind := flags["ball"]
+	if kind == "" {
+		kind = "poke"
+	}
+	if _, ok := pokeBalls[kind]; !ok {
+		return fmt.Errorf("unknown ball: %s", kind)
+	}
+	name := ""
+	if len(positional) > 0 {
+		name = positional[0]
+	}
+	encounter, err := findEncounter(conf, name)
+	if err != nil {
+		return err
+	}
+	if encounter == nil {
+		fmt.Println("You searched " + conf.location.Name + " but no wild " + name + " appeared")
+		return nil
+	}
+	name = encounter.pokemon
+
+	pokemon, err := fetchPokemon(conf, name)
+	if err != nil {
+		return err
+	}
+	species, err := fetchSpecies(conf, pokemon)
+	if err != nil {
+		return err
+	}
+
+	ball, err := conf.trainer.takeBall(kind)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Throwing a " + ball.name + " at " + name + "... (" + strconv.Itoa(conf.trainer.count(ballItem(kind))) + " left)")
+	shakes, caught := attemptCatch(conf.rng, catchInput{
+		captureRate: species.CaptureRate,
+		maxHP:       encounter.maxHP,
+		currentHP:   encounter.hp,
+		ballBonus:   ball.modifier(ballContext{types: pokemonTypes(pokemon), now: time.Now()}),
+	})
+	for i := 0; i < shakes && i < 3; i++ {
+		fmt.Println("...wobble...")
+	}
+	if !caught {
+		fmt.Println(name + " escaped!")
+		return nil
+	}
+
+	fmt.Println(name + " was caught!")
+	setEncounter(conf, nil)
+	entry := addToPokedex(conf, pokemon, encounter.level)
+	entry.Shiny = rollShiny(conf.rng, conf.shinyOdds)
+	entry.Gender = rollGender(conf.rng, species.GenderRate)
+	if entry.Shiny {
+		fmt.Println("Wow, it's a shiny " + name + "!")
+	}
+	entry.Friendship = species.BaseHappiness
+	setupGrowth(conf.rng, entry, species)
+	entry.Moves = defaultMoveset(pokemon, conf.versionGroup, entry.Level)
+	fmt.Println("Added to your pokedex with id " + strconv.Itoa(entry.ID) + " and sent to " + storageName(conf.trainer, entry.ID))
+	if held, ok := rollHeldItem(conf.rng, pokemon, conf.version); ok {
+		if err := pickUpItem(conf, held); err != nil {
+			return err
+		}
+		fmt.Println(name + " was holding a " + held + ", it was put in your bag")
+	}
+	return nil
+}
+
+// findEncounter returns the wild pokemon to throw at. Without a name it is
+// the pokemon met by walking, fishing or surfing; with a name that differs
+// from it, the current area is searched for that species instead. A nil
+// encounter means the search turned up nothing.
+func findEncounter(conf *config, name string) (*wildEncounter, error) {
+	if conf.encounter != nil && (name == "" || name == conf.encounter.pokemon) {
+		return conf.encounter, nil
+	}
+	if name == "" {
+		return nil, fmt.Errorf("usage: catch <pokemon> [--ball poke|great|ultra|master|net|dusk]")
+	}
+	if conf.location == nil {
+		return nil, fmt.Errorf("you need to explore or goto a location area first")
+	}
+	encounter, found, err := rollSpeciesEncounter(conf.rng, *conf.location, conf.version, name)
+	if err != nil {
+		return nil, fmt.Errorf("%s%s", err, didYouMean(conf, "pokemon", name))
+	}
+	if !found {
+		return nil, nil
+	}
+	fmt.Println("A wild " + name + " (Lv. " + strconv.Itoa(encounter.level) + ") appeared!")
+	setEncounter(conf, &encounter)
+	return conf.encounter, nil
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/jamistoso/pokedexcli/internal/pokeapi"
+)
+
+// effectEntry is one language's description of what a move, ability or
+// item does.
+type effectEntry struct {
+	Effect      string                `json:"effect"`
+	ShortEffect string                `json:"short_effect"`
+	Language    pokeapi.NamedResource `json:"language"`
+}
+
+// effectText returns the effect in the given language with the API's line
+// wrapping removed, or an empty string when there is none.
+func effectText(entries []effectEntry, language string) string {
+	for _, entry := range entries {
+		if entry.Language.Name == language {
+			return strings.Join(strings.Fields(entry.Effect), " ")
+		}
+	}
+	return ""
+}
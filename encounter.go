@@ -0,0 +1,273 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// encounterSlot is a single entry of a location area's encounter table:
+// one pokemon, found in one version by one method at a level range.
+type encounterSlot struct {
+	pokemon    string
+	version    string
+	method     string
+	chance     int
+	maxChance  int
+	minLevel   int
+	maxLevel   int
+	conditions []string
+}
+
+// wildEncounter is the wild pokemon currently in front of the player. hp
+// and maxHP stay zero until a battle starts, and carry over to later
+// battles and catch attempts after running from one.
+type wildEncounter struct {
+	pokemon string
+	level   int
+	method  string
+	hp      int
+	maxHP   int
+}
+
+// encounterSlots flattens the nested encounter data of an area.
+func encounterSlots(area locationArea) []encounterSlot {
+	slots := []encounterSlot{}
+	for _, encounter := range area.PokemonEncounters {
+		for _, versionDetail := range encounter.VersionDetails {
+			for _, detail := range versionDetail.EncounterDetails {
+				conditions := []string{}
+				for _, condition := range detail.ConditionValues {
+					conditions = append(conditions, condition.Name)
+				}
+				slots = append(slots, encounterSlot{
+					pokemon:    encounter.Pokemon.Name,
+					version:    versionDetail.Version.Name,
+					method:     detail.Method.Name,
+					chance:     detail.Chance,
+					maxChance:  versionDetail.MaxChance,
+					minLevel:   detail.MinLevel,
+					maxLevel:   detail.MaxLevel,
+					conditions: conditions,
+				})
+			}
+		}
+	}
+	return slots
+}
+
+// pickSlot chooses one of the slots weighted by its encounter chance.
+func pickSlot(rng *rand.Rand, slots []encounterSlot) encounterSlot {
+	total := 0
+	for _, slot := range slots {
+		total += slot.chance
+	}
+	if total <= 0 {
+		return slots[rng.Intn(len(slots))]
+	}
+	roll := rng.Intn(total)
+	for _, slot := range slots {
+		if roll < slot.chance {
+			return slot
+		}
+		roll -= slot.chance
+	}
+	return slots[len(slots)-1]
+}
+
+func rollLevel(rng *rand.Rand, slot encounterSlot) int {
+	if slot.maxLevel <= slot.minLevel {
+		return slot.minLevel
+	}
+	return slot.minLevel + rng.Intn(slot.maxLevel-slot.minLevel+1)
+}
+
+// rollSpeciesEncounter searches the area for a specific species within the
+// selected game version. The species shows up with its best per-version
+// chance; when it does, the level comes from one of its encounter slots.
+// It reports false when the search turned up nothing.
+func rollSpeciesEncounter(rng *rand.Rand, area locationArea, version string, name string) (wildEncounter, bool, error) {
+	slots := []encounterSlot{}
+	maxChance := 0
+	for _, slot := range encounterSlots(area) {
+		if slot.pokemon != name || !inVersion(version, slot.version) {
+			continue
+		}
+		slots = append(slots, slot)
+		maxChance = max(maxChance, slot.maxChance)
+	}
+	if len(slots) == 0 {
+		return wildEncounter{}, false, fmt.Errorf("there are no wild %s in %s", name, area.Name)
+	}
+	if rng.Intn(100) >= maxChance {
+		return wildEncounter{}, false, nil
+	}
+
+	slot := pickSlot(rng, slots)
+	return wildEncounter{
+		pokemon: name,
+		level:   rollLevel(rng, slot),
+		method:  slot.method,
+	}, true, nil
+}
+
+// rollWildEncounter picks a random encounter in the area among the slots
+// for the given method, weighted by their chance. Slots are limited to a
+// single game version so chances from different games are not mixed; when
+// no version is selected the first one listed by the area is used.
+func rollWildEncounter(rng *rand.Rand, area locationArea, version string, method string) (wildEncounter, error) {
+	slots := []encounterSlot{}
+	for _, slot := range encounterSlots(area) {
+		if slot.method != method {
+			continue
+		}
+		if version == "" {
+			version = slot.version
+		}
+		if slot.version == version {
+			slots = append(slots, slot)
+		}
+	}
+	if len(slots) == 0 {
+		return wildEncounter{}, fmt.Errorf("there is nothing to find by %s in %s", method, area.Name)
+	}
+
+	slot := pickSlot(rng, slots)
+	return wildEncounter{
+		pokemon: slot.pokemon,
+		level:   rollLevel(rng, slot),
+		method:  slot.method,
+	}, nil
+}
+
+func searchArea(conf *config, method string) error {
+	if conf.location == nil {
+		return fmt.Errorf("you need to explore or goto a location area first")
+	}
+	encounter, err := rollWildEncounter(conf.rng, *conf.location, conf.version, method)
+	if err != nil {
+		return err
+	}
+	setEncounter(conf, &encounter)
+	fmt.Println("A wild " + encounter.pokemon + " (Lv. " + strconv.Itoa(encounter.level) + ") appeared!")
+	fmt.Println("Use catch to throw a ball at it")
+	return nil
+}
+
+func commandWalk(conf *config, args ...string) error {
+	return searchArea(conf, "walk")
+}
+
+func commandSurf(conf *config, args ...string) error {
+	return searchArea(conf, "surf")
+}
+
+func commandFish(conf *config, args ...string) error {
+	rod := "old"
+	if len(args) > 0 {
+		rod = args[0]
+	}
+	switch rod {
+	case "old", "good", "super":
+		return searchArea(conf, rod+"-rod")
+	default:
+		return fmt.Errorf("usage: fish [old|good|super]")
+	}
+}
+
+// methodRate returns the area's base encounter rate for a method, the
+// chance per step (or cast) of meeting any wild pokemon at all.
+func methodRate(area locationArea, version string, method string) (int, bool) {
+	for _, rate := range area.EncounterMethodRates {
+		if rate.EncounterMethod.Name != method {
+			continue
+		}
+		for _, detail := range rate.VersionDetails {
+			if inVersion(version, detail.Version.Name) {
+				return detail.Rate, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// slotOrder returns how encounter slots are sorted: "rarity" for the
+// rarest first, "common" for the most common first, or empty to keep the
+// API's order, which gives a nil function.
+func slotOrder(sortBy string) (func(a, b encounterSlot) bool, error) {
+	switch sortBy {
+	case "":
+		return nil, nil
+	case "rarity":
+		return func(a, b encounterSlot) bool { return a.chance < b.chance }, nil
+	case "common":
+		return func(a, b encounterSlot) bool { return a.chance > b.chance }, nil
+	default:
+		return nil, fmt.Errorf("unknown sort order: %s", sortBy)
+	}
+}
+
+// printEncounterTables prints one table per encounter method with the
+// chance, level range and conditions of every slot, ordered by less when
+// it is set.
+func printEncounterTables(area locationArea, version string, less func(a, b encounterSlot) bool) {
+	methods := []string{}
+	byMethod := map[string][]encounterSlot{}
+	for _, slot := range encounterSlots(area) {
+		if !inVersion(version, slot.version) {
+			continue
+		}
+		if _, ok := byMethod[slot.method]; !ok {
+			methods = append(methods, slot.method)
+		}
+		byMethod[slot.method] = append(byMethod[slot.method], slot)
+	}
+
+	for _, method := range methods {
+		slots := byMethod[method]
+		if less != nil {
+			sort.SliceStable(slots, func(i, j int) bool { return less(slots[i], slots[j]) })
+		}
+
+		header := method
+		if rate, ok := methodRate(area, version, method); ok {
+			header += " (encounter rate " + strconv.Itoa(rate) + "%)"
+		}
+		fmt.Println()
+		fmt.Println(header)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		columns := "POKEMON\tCHANCE\tLEVELS\tCONDITIONS"
+		if version == "" {
+			columns += "\tVERSION"
+		}
+		fmt.Fprintln(w, columns)
+		for _, slot := range slots {
+			levels := strconv.Itoa(slot.minLevel)
+			if slot.maxLevel > slot.minLevel {
+				levels += "-" + strconv.Itoa(slot.maxLevel)
+			}
+			conditions := strings.Join(slot.conditions, ", ")
+			if conditions == "" {
+				conditions = "-"
+			}
+			row := slot.pokemon + "\t" + strconv.Itoa(slot.chance) + "%\t" + levels + "\t" + conditions
+			if version == "" {
+				row += "\t" + slot.version
+			}
+			fmt.Fprintln(w, row)
+		}
+		w.Flush()
+	}
+}
+
+// setEncounter replaces the current wild encounter. Any battle with the
+// previous one is over.
+func setEncounter(conf *config, encounter *wildEncounter) {
+	conf.encounter = encounter
+	conf.battle = nil
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jamistoso/pokedexcli/internal/pokeapi"
+)
+
+type evolutionDetail struct {
+	Item                  *pokeapi.NamedResource `json:"item"`
+	Trigger               pokeapi.NamedResource  `json:"trigger"`
+	Gender                *int                   `json:"gender"`
+	HeldItem              *pokeapi.NamedResource `json:"held_item"`
+	KnownMove             *pokeapi.NamedResource `json:"known_move"`
+	KnownMoveType         *pokeapi.NamedResource `json:"known_move_type"`
+	Location              *pokeapi.NamedResource `json:"location"`
+	MinLevel              *int                   `json:"min_level"`
+	MinHappiness          *int                   `json:"min_happiness"`
+	MinBeauty             *int                   `json:"min_beauty"`
+	MinAffection          *int                   `json:"min_affection"`
+	NeedsOverworldRain    bool                   `json:"needs_overworld_rain"`
+	PartySpecies          *pokeapi.NamedResource `json:"party_species"`
+	PartyType             *pokeapi.NamedResource `json:"party_type"`
+	RelativePhysicalStats *int                   `json:"relative_physical_stats"`
+	TimeOfDay             string                 `json:"time_of_day"`
+	TradeSpecies          *pokeapi.NamedResource `json:"trade_species"`
+	TurnUpsideDown        bool                   `json:"turn_upside_down"`
+}
+
+type chainLink struct {
+	IsBaby           bool                  `json:"is_baby"`
+	Species          pokeapi.NamedResource `json:"species"`
+	EvolutionDetails []evolutionDetail     `json:"evolution_details"`
+	EvolvesTo        []chainLink           `json:"evolves_to"`
+}
+
+type evolutionChain struct {
+	ID    int       `json:"id"`
+	Chain chainLink `json:"chain"`
+}
+
+func fetchEvolutionChain(conf *config, species pokemonSpecies) (evolutionChain, error) {
+	if species.EvolutionChain.URL == "" {
+		return evolutionChain{}, fmt.Errorf("%s has no evolution chain", species.Name)
+	}
+	data, err := fetch(conf, species.EvolutionChain.URL)
+	if err != nil {
+		return evolutionChain{}, err
+	}
+	var chain evolutionChain
+	if err := json.Unmarshal(data, &chain); err != nil {
+		return evolutionChain{}, fmt.Errorf("evolution chain retrieval failed: %s", err)
+	}
+	return chain, nil
+}
+
+// describe turns the conditions of an evolution into a short summary such
+// as "level-up: level 16" or "trade: holding metal-coat".
+func (d evolutionDetail) describe() string {
+	conditions := []string{}
+	if d.MinLevel != nil {
+		conditions = append(conditions, "level "+strconv.Itoa(*d.MinLevel))
+	}
+	if d.Item != nil {
+		conditions = append(conditions, d.Item.Name)
+	}
+	if d.HeldItem != nil {
+		conditions = append(conditions, "holding "+d.HeldItem.Name)
+	}
+	if d.MinHappiness != nil {
+		conditions = append(conditions, "friendship "+strconv.Itoa(*d.MinHappiness))
+	}
+	if d.MinAffection != nil {
+		conditions = append(conditions, "affection "+strconv.Itoa(*d.MinAffection))
+	}
+	if d.MinBeauty != nil {
+		conditions = append(conditions, "beauty "+strconv.Itoa(*d.MinBeauty))
+	}
+	if d.TimeOfDay != "" {
+		conditions = append(conditions, "during the "+d.TimeOfDay)
+	}
+	if d.KnownMove != nil {
+		conditions = append(conditions, "knowing "+d.KnownMove.Name)
+	}
+	if d.KnownMoveType != nil {
+		conditions = append(conditions, "knowing a "+d.KnownMoveType.Name+" move")
+	}
+	if d.Location != nil {
+		conditions = append(conditions, "at "+d.Location.Name)
+	}
+	if d.Gender != nil {
+		conditions = append(conditions, evolutionGender(*d.Gender))
+	}
+	if d.PartySpecies != nil {
+		conditions = append(conditions, "with "+d.PartySpecies.Name+" in the party")
+	}
+	if d.PartyType != nil {
+		conditions = append(conditions, "with a "+d.PartyType.Name+" type in the party")
+	}
+	if d.TradeSpecies != nil {
+		conditions = append(conditions, "for "+d.TradeSpecies.Name)
+	}
+	if d.RelativePhysicalStats != nil {
+		switch *d.RelativePhysicalStats {
+		case 1:
+			conditions = append(conditions, "attack > defense")
+		case -1:
+			conditions = append(conditions, "attack < defense")
+		case 0:
+			conditions = append(conditions, "attack = defense")
+		}
+	}
+	if d.NeedsOverworldRain {
+		conditions = append(conditions, "while raining")
+	}
+	if d.TurnUpsideDown {
+		conditions = append(conditions, "upside down")
+	}
+	if len(conditions) == 0 {
+		return d.Trigger.Name
+	}
+	return d.Trigger.Name + ": " + strings.Join(conditions, ", ")
+}
+
+// caughtSpecies returns the species names present in the pokedex.
+func caughtSpecies(conf *config) map[string]bool {
+	species := map[string]bool{}
+	for _, caught := range conf.pokedex {
+		name := caught.Pokemon.Species.Name
+		if name == "" {
+			name = caught.Pokemon.Name
+		}
+		species[name] = true
+	}
+	return species
+}
+
+// renderChain draws the chain as a tree, one species per line.
+func renderChain(link chainLink, caught map[string]bool) []string {
+	lines := []string{chainLabel(link, caught)}
+	lines = append(lines, renderBranches(link.EvolvesTo, "", caught)...)
+	return lines
+}
+
+func renderBranches(links []chainLink, prefix string, caught map[string]bool) []string {
+	lines := []string{}
+	for i, link := range links {
+		branch, indent := "├── ", "│   "
+		if i == len(links)-1 {
+			branch, indent = "└── ", "    "
+		}
+		lines = append(lines, prefix+branch+chainLabel(link, caught))
+		lines = append(lines, renderBranches(link.EvolvesTo, prefix+indent, caught)...)
+	}
+	return lines
+}
+
+func chainLabel(link chainLink, caught map[string]bool) string {
+	label := link.Species.Name
+	if link.IsBaby {
+		label += " (baby)"
+	}
+	if len(link.EvolutionDetails) > 0 {
+		methods := []string{}
+		for _, detail := range link.EvolutionDetails {
+			methods = append(methods, detail.describe())
+		}
+		label += " [" + strings.Join(methods, " | ") + "]"
+	}
+	if caught[link.Species.Name] {
+		label += " *caught*"
+	}
+	return label
+}
+
+func commandEvolution(conf *config, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: evolution <name>")
+	}
+	species, err := fetchSpeciesURL(conf, pokemonSpeciesURL+args[0])
+	if err != nil {
+		return err
+	}
+	chain, err := fetchEvolutionChain(conf, species)
+	if err != nil {
+		return err
+	}
+	for _, line := range renderChain(chain.Chain, caughtSpecies(conf)) {
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// findLink returns the link of the chain for the given species.
+func findLink(link chainLink, species string) (chainLink, bool) {
+	if link.Species.Name == species {
+		return link, true
+	}
+	for _, next := range link.EvolvesTo {
+		if found, ok := findLink(next, species); ok {
+			return found, true
+		}
+	}
+	return chainLink{}, false
+}
+
+func timeOfDay(t time.Time) string {
+	hour := t.Hour()
+	switch {
+	case hour >= 18 || hour < 6:
+		return "night"
+	case hour == 17:
+		return "dusk"
+	default:
+		return "day"
+	}
+}
+
+// evolutionGender names the gender id used by evolution details.
+func evolutionGender(id int) string {
+	if id == 1 {
+		return "female"
+	}
+	return "male"
+}
+
+// evolveContext is what evolution conditions are checked against besides
+// the caught pokemon itself.
+type evolveContext struct {
+	now      time.Time
+	traded   bool
+	usedItem string
+}
+
+// unmetCondition returns the first condition of the detail the caught
+// pokemon does not meet, or an empty string when it can evolve.
+func unmetCondition(d evolutionDetail, caught *caughtPokemon, ctx evolveContext) string {
+	switch d.Trigger.Name {
+	case "level-up":
+	case "trade":
+		if !ctx.traded {
+			return "needs to be traded (use --trade)"
+		}
+	case "use-item":
+		if d.Item == nil || d.Item.Name != ctx.usedItem {
+			return "needs a " + nameOrUnknown(d.Item) + " to be used on it"
+		}
+	default:
+		return "evolves by " + d.Trigger.Name + ", which is not supported"
+	}
+	if d.MinLevel != nil && caught.Level < *d.MinLevel {
+		return "needs to reach level " + strconv.Itoa(*d.MinLevel)
+	}
+	if d.MinHappiness != nil && caught.Friendship < *d.MinHappiness {
+		return "needs friendship " + strconv.Itoa(*d.MinHappiness)
+	}
+	if d.HeldItem != nil && caught.HeldItem != d.HeldItem.Name {
+		return "needs to hold a " + d.HeldItem.Name
+	}
+	if d.Gender != nil && caught.Gender != evolutionGender(*d.Gender) {
+		return "only evolves if " + evolutionGender(*d.Gender)
+	}
+	if d.TimeOfDay != "" && timeOfDay(ctx.now) != d.TimeOfDay {
+		return "only evolves during the " + d.TimeOfDay
+	}
+	if d.KnownMove != nil || d.KnownMoveType != nil || d.Location != nil || d.PartySpecies != nil ||
+		d.PartyType != nil || d.TradeSpecies != nil || d.MinBeauty != nil || d.MinAffection != nil ||
+		d.NeedsOverworldRain || d.TurnUpsideDown || d.RelativePhysicalStats != nil {
+		return "has conditions that are not supported (" + d.describe() + ")"
+	}
+	return ""
+}
+
+// evolutionTarget picks the species the caught pokemon can evolve into.
+// With a target only that branch is considered. When nothing qualifies the
+// error lists what each branch still needs.
+func evolutionTarget(link chainLink, caught *caughtPokemon, target string, ctx evolveContext) (chainLink, evolutionDetail, error) {
+	if len(link.EvolvesTo) == 0 {
+		return chainLink{}, evolutionDetail{}, fmt.Errorf("%s does not evolve any further", link.Species.Name)
+	}
+	reasons := []string{}
+	for _, next := range link.EvolvesTo {
+		if target != "" && next.Species.Name != target {
+			continue
+		}
+		for _, detail := range next.EvolutionDetails {
+			reason := unmetCondition(detail, caught, ctx)
+			if reason == "" {
+				return next, detail, nil
+			}
+			reasons = append(reasons, next.Species.Name+": "+reason)
+		}
+	}
+	if len(reasons) == 0 {
+		return chainLink{}, evolutionDetail{}, fmt.Errorf("%s does not evolve into %s", link.Species.Name, target)
+	}
+	return chainLink{}, evolutionDetail{}, fmt.Errorf("%s can't evolve yet:\n - %s", caught.displayName(), strings.Join(reasons, "\n - "))
+}
+
+// evolveInto swaps the species of a caught pokemon, keeping everything
+// else about it.
+func evolveInto(conf *config, caught *caughtPokemon, species string) error {
+	evolved, err := fetchPokemon(conf, species)
+	if err != nil {
+		return err
+	}
+	from := caught.Pokemon.Name
+	caught.Pokemon = evolved
+	caught.record("evolved from " + from + " into " + evolved.Name + " at Lv. " + strconv.Itoa(caught.Level))
+	fmt.Println("Congratulations! " + caught.displayName() + " evolved from " + from + " into " + evolved.Name + "!")
+	return nil
+}
+
+// evolutionLink finds the caught pokemon's place in its evolution chain.
+func evolutionLink(conf *config, caught *caughtPokemon) (chainLink, error) {
+	species, err := fetchSpecies(conf, caught.Pokemon)
+	if err != nil {
+		return chainLink{}, err
+	}
+	chain, err := fetchEvolutionChain(conf, species)
+	if err != nil {
+		return chainLink{}, err
+	}
+	link, ok := findLink(chain.Chain, species.Name)
+	if !ok {
+		return chainLink{}, fmt.Errorf("%s is missing from its own evolution chain", species.Name)
+	}
+	return link, nil
+}
+
+func commandEvolve(conf *config, args ...string) error {
+	positional, flags := parseFlags(args, "trade")
+	if len(positional) == 0 {
+		return fmt.Errorf("usage: evolve <id> [<species>] [--trade]")
+	}
+	caught, err := findCaughtByID(conf, positional[0])
+	if err != nil {
+		return err
+	}
+	target := ""
+	if len(positional) > 1 {
+		target = positional[1]
+	}
+	_, traded := flags["trade"]
+
+	link, err := evolutionLink(conf, caught)
+	if err != nil {
+		return err
+	}
+	next, detail, err := evolutionTarget(link, caught, target, evolveContext{now: time.Now(), traded: traded})
+	if err != nil {
+		return err
+	}
+	if detail.Trigger.Name == "trade" && detail.HeldItem != nil {
+		caught.HeldItem = ""
+	}
+	return evolveInto(conf, caught, next.Species.Name)
+}
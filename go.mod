@@ -6,3 +6,8 @@ require (
 	github.com/jamistoso/pokedexcli/internal/pokeapi v0.0.0-20241213211644-0d39163c7644
 	github.com/jamistoso/pokedexcli/internal/pokecache v0.0.0-20241213211644-0d39163c7644
 )
+
+replace (
+	github.com/jamistoso/pokedexcli/internal/pokeapi => ./internal/pokeapi
+	github.com/jamistoso/pokedexcli/internal/pokecache => ./internal/pokecache
+)
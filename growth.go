@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+)
+
+const maxLevel = 100
+const maxIV = 31
+const maxStatEVs = 252
+const maxTotalEVs = 510
+
+// natures maps each nature to the stat it raises and the stat it lowers by
+// 10%. Neutral natures raise and lower the same stat.
+var natures = map[string][2]string{
+	"hardy":   {"attack", "attack"},
+	"lonely":  {"attack", "defense"},
+	"brave":   {"attack", "speed"},
+	"adamant": {"attack", "special-attack"},
+	"naughty": {"attack", "special-defense"},
+	"bold":    {"defense", "attack"},
+	"docile":  {"defense", "defense"},
+	"relaxed": {"defense", "speed"},
+	"impish":  {"defense", "special-attack"},
+	"lax":     {"defense", "special-defense"},
+	"timid":   {"speed", "attack"},
+	"hasty":   {"speed", "defense"},
+	"serious": {"speed", "speed"},
+	"jolly":   {"speed", "special-attack"},
+	"naive":   {"speed", "special-defense"},
+	"modest":  {"special-attack", "attack"},
+	"mild":    {"special-attack", "defense"},
+	"quiet":   {"special-attack", "speed"},
+	"bashful": {"special-attack", "special-attack"},
+	"rash":    {"special-attack", "special-defense"},
+	"calm":    {"special-defense", "attack"},
+	"gentle":  {"special-defense", "defense"},
+	"sassy":   {"special-defense", "speed"},
+	"careful": {"special-defense", "special-attack"},
+	"quirky":  {"special-defense", "special-defense"},
+}
+
+func natureModifier(nature string, stat string) float64 {
+	effect, ok := natures[nature]
+	if !ok || effect[0] == effect[1] {
+		return 1
+	}
+	switch stat {
+	case effect[0]:
+		return 1.1
+	case effect[1]:
+		return 0.9
+	default:
+		return 1
+	}
+}
+
+// calcStats applies the mainline stat formulas. Missing IVs and EVs count
+// as zero and an unknown nature as neutral.
+func calcStats(base, ivs, evs map[string]int, nature string, level int) map[string]int {
+	stats := map[string]int{}
+	for _, name := range statNames {
+		core := (2*base[name] + ivs[name] + evs[name]/4) * level / 100
+		if name == "hp" {
+			stats[name] = core + level + 10
+		} else {
+			stats[name] = int(float64(core+5) * natureModifier(nature, name))
+		}
+	}
+	return stats
+}
+
+// experienceForLevel returns the total experience needed to reach a level
+// on the species' growth rate.
+func experienceForLevel(growthRate string, level int) int {
+	if level <= 1 {
+		return 0
+	}
+	n := level
+	cube := n * n * n
+	switch growthRate {
+	case "fast":
+		return 4 * cube / 5
+	case "slow":
+		return 5 * cube / 4
+	case "medium-slow":
+		return 6*cube/5 - 15*n*n + 100*n - 140
+	case "slow-then-very-fast":
+		switch {
+		case n < 50:
+			return cube * (100 - n) / 50
+		case n < 68:
+			return cube * (150 - n) / 100
+		case n < 98:
+			return cube * ((1911 - 10*n) / 3) / 500
+		default:
+			return cube * (160 - n) / 100
+		}
+	case "fast-then-very-slow":
+		switch {
+		case n < 15:
+			return cube * ((n+1)/3 + 24) / 50
+		case n < 36:
+			return cube * (n + 14) / 50
+		default:
+			return cube * (n/2 + 32) / 50
+		}
+	default:
+		return cube
+	}
+}
+
+// setupGrowth rolls the individual values and nature of a newly caught
+// pokemon and gives it the experience its level is worth.
+func setupGrowth(rng *rand.Rand, caught *caughtPokemon, species pokemonSpecies) {
+	caught.GrowthRate = species.GrowthRate.Name
+	caught.Experience = experienceForLevel(caught.GrowthRate, caught.Level)
+	caught.IVs = map[string]int{}
+	caught.EVs = map[string]int{}
+	for _, name := range statNames {
+		caught.IVs[name] = rng.Intn(maxIV + 1)
+	}
+	names := []string{}
+	for name := range natures {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	caught.Nature = names[rng.Intn(len(names))]
+}
+
+func (c *caughtPokemon) stats() map[string]int {
+	return calcStats(baseStats(c.Pokemon), c.IVs, c.EVs, c.Nature, c.Level)
+}
+
+// battleExperience is the experience for defeating a wild pokemon.
+func battleExperience(defeated Pokemon, level int) int {
+	return max(defeated.BaseExperience*level/7, 1)
+}
+
+// gainEffort adds the effort values a defeated pokemon yields, respecting
+// the per-stat and total caps.
+func (c *caughtPokemon) gainEffort(defeated Pokemon) {
+	for _, stat := range defeated.Stats {
+		c.addEffort(stat.Stat.Name, stat.Effort)
+	}
+}
+
+// addEffort raises one stat's effort values by up to amount within the caps
+// and returns how much it rose.
+func (c *caughtPokemon) addEffort(stat string, amount int) int {
+	if c.EVs == nil {
+		c.EVs = map[string]int{}
+	}
+	total := 0
+	for _, ev := range c.EVs {
+		total += ev
+	}
+	gain := min(amount, maxStatEVs-c.EVs[stat], maxTotalEVs-total)
+	if gain <= 0 {
+		return 0
+	}
+	c.EVs[stat] += gain
+	return gain
+}
+
+// gainExperience adds experience and returns the levels gained.
+func (c *caughtPokemon) gainExperience(xp int) []int {
+	c.Experience += xp
+	levels := []int{}
+	for c.Level < maxLevel && c.Experience >= experienceForLevel(c.GrowthRate, c.Level+1) {
+		c.Level++
+		levels = append(levels, c.Level)
+		c.record("reached Lv. " + strconv.Itoa(c.Level))
+	}
+	return levels
+}
+
+func printComputedStats(caught *caughtPokemon) {
+	nature := caught.Nature
+	if nature == "" {
+		nature = "unknown"
+	}
+	toNext := "max level"
+	if caught.Level < maxLevel {
+		toNext = strconv.Itoa(experienceForLevel(caught.GrowthRate, caught.Level+1)-caught.Experience) + " to next level"
+	}
+	fmt.Println("Nature: " + nature)
+	fmt.Println("Experience: " + strconv.Itoa(caught.Experience) + " (" + toNext + ")")
+	fmt.Println("Stats at Lv. " + strconv.Itoa(caught.Level) + ":")
+
+	stats := caught.stats()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "	STAT\tVALUE\tIV\tEV")
+	for _, name := range statNames {
+		fmt.Fprintln(w, "	"+name+"\t"+strconv.Itoa(stats[name])+"\t"+strconv.Itoa(caught.IVs[name])+"\t"+strconv.Itoa(caught.EVs[name]))
+	}
+	w.Flush()
+}
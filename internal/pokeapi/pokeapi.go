@@ -1,16 +1,54 @@
 package pokeapi
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"iter"
 	"net/http"
 )
 
+const BaseURL = "https://pokeapi.co/api/v2/"
+
+// ErrNotFound is returned when the API has no resource at the requested URL.
+var ErrNotFound = errors.New("not found")
+
+// NamedResource is a reference to a named resource, e.g. one pokemon in
+// the /pokemon list or the type of a move.
+type NamedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type namedResourceList struct {
+	Count    int             `json:"count"`
+	Next     string          `json:"next"`
+	Previous string          `json:"previous"`
+	Results  []NamedResource `json:"results"`
+}
+
 func PokeapiGet(url string) ([]byte, error) {
-	res, err := http.Get(url)
+	return PokeapiGetContext(context.Background(), url)
+}
+
+func PokeapiGetContext(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if res.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status: %s", res.Status)
+	}
 
 	data, err := io.ReadAll(res.Body)
 	if err != nil {
@@ -20,3 +58,33 @@ func PokeapiGet(url string) ([]byte, error) {
 
 	return data, nil
 }
+
+// ListAll yields every entry of a named resource list such as "pokemon",
+// "item", "move", "berry" or "type", following the next links page by
+// page. Iteration stops at the first error, which is yielded last.
+func ListAll(ctx context.Context, resource string) iter.Seq2[NamedResource, error] {
+	return listAll(ctx, BaseURL+resource+"?limit=100")
+}
+
+func listAll(ctx context.Context, url string) iter.Seq2[NamedResource, error] {
+	return func(yield func(NamedResource, error) bool) {
+		for url != "" {
+			data, err := PokeapiGetContext(ctx, url)
+			if err != nil {
+				yield(NamedResource{}, err)
+				return
+			}
+			var page namedResourceList
+			if err := json.Unmarshal(data, &page); err != nil {
+				yield(NamedResource{}, fmt.Errorf("decoding %s: %w", url, err))
+				return
+			}
+			for _, res := range page.Results {
+				if !yield(res, nil) {
+					return
+				}
+			}
+			url = page.Next
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListAll(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("offset") {
+		case "":
+			fmt.Fprintf(w, `{"count": 3, "next": "%s/pokemon?offset=2", "results": [{"name": "bulbasaur"}, {"name": "ivysaur"}]}`, server.URL)
+		case "2":
+			fmt.Fprint(w, `{"count": 3, "next": null, "results": [{"name": "venusaur"}]}`)
+		}
+	}))
+	defer server.Close()
+
+	names := []string{}
+	for res, err := range listAll(context.Background(), server.URL+"/pokemon") {
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+			return
+		}
+		names = append(names, res.Name)
+	}
+	if fmt.Sprint(names) != "[bulbasaur ivysaur venusaur]" {
+		t.Errorf("expected all three pages of names, got %v", names)
+	}
+
+	count := 0
+	for range listAll(context.Background(), server.URL+"/pokemon") {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("expected iteration to stop early")
+	}
+}
@@ -14,6 +14,7 @@ type Cache struct {
 type cacheEntry struct {
 	createdAt	time.Time
 	val 		[]byte
+	ttl			time.Duration
 }
 
 func NewCache(duration time.Duration) (Cache) {
@@ -27,9 +28,16 @@ func NewCache(duration time.Duration) (Cache) {
 }
 
 func (c Cache) Add(key string, val []byte) {
+	c.AddFor(key, val, c.interval)
+}
+
+// AddFor stores an entry that outlives the cache's default interval, for
+// data that is expensive to rebuild.
+func (c Cache) AddFor(key string, val []byte, ttl time.Duration) {
 	entry := cacheEntry{
 		createdAt: 	time.Now(),
 		val:		val,
+		ttl:		ttl,
 	}
 	c.cacheMux.Lock()
 	c.cacheMap[key] = entry
@@ -54,7 +62,7 @@ func (c Cache) reapLoop() {
 		<-ticker.C
 		c.cacheMux.Lock()
 		for key := range c.cacheMap {
-			if time.Since(c.cacheMap[key].createdAt) > c.interval {
+			if time.Since(c.cacheMap[key].createdAt) > c.cacheMap[key].ttl {
 				delete(c.cacheMap, key)
 			}
 		}
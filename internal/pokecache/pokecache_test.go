@@ -59,4 +59,23 @@ func TestReapLoop(t *testing.T) {
 		t.Errorf("expected to not find key")
 		return
 	}
-}
\ No newline at end of file
+}
+
+func TestAddForOutlivesInterval(t *testing.T) {
+	const baseTime = 5 * time.Millisecond
+	const waitTime = baseTime + 5*time.Millisecond
+	cache := NewCache(baseTime)
+	cache.AddFor("https://example.com/index", []byte("testdata"), time.Minute)
+	cache.Add("https://example.com", []byte("testdata"))
+
+	time.Sleep(waitTime)
+
+	if _, ok := cache.Get("https://example.com/index"); !ok {
+		t.Errorf("expected to find long-lived key")
+		return
+	}
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected to not find key")
+		return
+	}
+}
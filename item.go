@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jamistoso/pokedexcli/internal/pokeapi"
+)
+
+type item struct {
+	ID            int                     `json:"id"`
+	Name          string                  `json:"name"`
+	Cost          int                     `json:"cost"`
+	FlingPower    *int                    `json:"fling_power"`
+	Category      pokeapi.NamedResource   `json:"category"`
+	Attributes    []pokeapi.NamedResource `json:"attributes"`
+	EffectEntries []effectEntry           `json:"effect_entries"`
+	Machines      []struct {
+		Machine struct {
+			URL string `json:"url"`
+		} `json:"machine"`
+		VersionGroup pokeapi.NamedResource `json:"version_group"`
+	} `json:"machines"`
+}
+
+// machine is a TM or HM and the move it teaches in one version group.
+type machine struct {
+	ID           int                   `json:"id"`
+	Move         pokeapi.NamedResource `json:"move"`
+	VersionGroup pokeapi.NamedResource `json:"version_group"`
+}
+
+func fetchItem(conf *config, name string) (item, error) {
+	data, err := fetch(conf, itemURL+name)
+	if err != nil {
+		return item{}, err
+	}
+	var it item
+	if err := json.Unmarshal(data, &it); err != nil {
+		return item{}, fmt.Errorf("item retrieval failed: %s", err)
+	}
+	return it, nil
+}
+
+// machineMove returns the move a TM or HM teaches in the version group.
+// Machines are renumbered between games, so without a version group the
+// newest game's move is used.
+func machineMove(conf *config, it item, versionGroup string) (string, error) {
+	url := ""
+	for _, m := range it.Machines {
+		if inVersion(versionGroup, m.VersionGroup.Name) {
+			url = m.Machine.URL
+		}
+	}
+	if url == "" {
+		return "", fmt.Errorf("%s is not a machine in %s", it.Name, versionGroup)
+	}
+	data, err := fetch(conf, url)
+	if err != nil {
+		return "", err
+	}
+	var m machine
+	if err := json.Unmarshal(data, &m); err != nil {
+		return "", fmt.Errorf("machine retrieval failed: %s", err)
+	}
+	return m.Move.Name, nil
+}
+
+func commandItem(conf *config, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: item <name>")
+	}
+	it, err := fetchItem(conf, args[0])
+	if err != nil {
+		return err
+	}
+	attributes := []string{}
+	for _, attr := range it.Attributes {
+		attributes = append(attributes, attr.Name)
+	}
+	fmt.Println(
+		"Name: "+it.Name,
+		"\nCategory: "+it.Category.Name,
+		"\nCost: "+strconv.Itoa(it.Cost),
+		"\nFling power: "+optionalInt(it.FlingPower),
+		"\nAttributes: "+strings.Join(attributes, ", "),
+	)
+	if effect := effectText(it.EffectEntries, conf.language); effect != "" {
+		fmt.Println()
+		fmt.Println(effect)
+	}
+	return nil
+}
+
+// wildHeldItemLines lists the items the species may hold in the wild with
+// their rarity in each version matching the selection.
+func wildHeldItemLines(pokemon Pokemon, version string) []string {
+	lines := []string{}
+	for _, held := range pokemon.HeldItems {
+		rarities := []string{}
+		for _, detail := range held.VersionDetails {
+			if inVersion(version, detail.Version.Name) {
+				rarities = append(rarities, strconv.Itoa(detail.Rarity)+"% in "+detail.Version.Name)
+			}
+		}
+		if len(rarities) > 0 {
+			lines = append(lines, "	- "+held.Item.Name+" ("+strings.Join(rarities, ", ")+")")
+		}
+	}
+	return lines
+}
+
+func printWildHeldItems(pokemon Pokemon, version string) {
+	lines := wildHeldItemLines(pokemon, version)
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Println("Wild held items:")
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jamistoso/pokedexcli/internal/pokeapi"
+)
+
+func commandList(conf *config, args ...string) error {
+	positional, flags := parseFlags(args)
+	if len(positional) == 0 {
+		return fmt.Errorf("usage: list <resource> [--limit <n>]")
+	}
+	resource := positional[0]
+	limit := 0
+	if val, ok := flags["limit"]; ok {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid limit: %s", val)
+		}
+		limit = n
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	count := 0
+	for res, err := range pokeapi.ListAll(ctx, resource) {
+		if err != nil {
+			return fmt.Errorf("listing %s failed: %s", resource, err)
+		}
+		fmt.Println(res.Name)
+		count++
+		if count == limit {
+			break
+		}
+	}
+	fmt.Println(strconv.Itoa(count) + " " + resource + " listed")
+	return nil
+}
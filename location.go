@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jamistoso/pokedexcli/internal/pokeapi"
+)
+
+func fetchLocationArea(conf *config, name string) (locationArea, error) {
+	data, err := fetch(conf, locationAreaURL+name)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return locationArea{}, fmt.Errorf("unknown location area: %s%s", name, didYouMean(conf, "location-area", name))
+	}
+	if err != nil {
+		return locationArea{}, err
+	}
+	area, err := getLocationArea(data)
+	if err != nil {
+		return locationArea{}, fmt.Errorf("location area retrieval failed: %s", err)
+	}
+	return area, nil
+}
+
+func commandGoto(conf *config, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: goto <location-area>")
+	}
+	area, err := fetchLocationArea(conf, args[0])
+	if err != nil {
+		return err
+	}
+	conf.location = &area
+	setEncounter(conf, nil)
+	fmt.Println("You are now at " + area.Name + " (" + area.Location.Name + ")")
+	return nil
+}
+
+type region struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	MainGeneration struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"main_generation"`
+	Locations []struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"locations"`
+}
+
+type location struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Region struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"region"`
+	Areas []struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"areas"`
+}
+
+func fetchRegion(conf *config, name string) (region, error) {
+	data, err := fetch(conf, regionURL+name)
+	if err != nil {
+		return region{}, err
+	}
+	var reg region
+	if err := json.Unmarshal(data, &reg); err != nil {
+		return region{}, fmt.Errorf("region retrieval failed: %s", err)
+	}
+	return reg, nil
+}
+
+func fetchLocation(conf *config, name string) (location, error) {
+	data, err := fetch(conf, locationURL+name)
+	if err != nil {
+		return location{}, err
+	}
+	var loc location
+	if err := json.Unmarshal(data, &loc); err != nil {
+		return location{}, fmt.Errorf("location retrieval failed: %s", err)
+	}
+	return loc, nil
+}
+
+func commandRegions(conf *config, args ...string) error {
+	data, err := fetch(conf, regionURL)
+	if err != nil {
+		return err
+	}
+	regions, err := getResults(data)
+	if err != nil {
+		return fmt.Errorf("region list retrieval failed: %s", err)
+	}
+	listResultNames(regions)
+	return nil
+}
+
+func commandRegion(conf *config, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: region <name>")
+	}
+	reg, err := fetchRegion(conf, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(reg.Name + " (" + reg.MainGeneration.Name + ")")
+	fmt.Println("Locations:")
+	for _, loc := range reg.Locations {
+		fmt.Println(" - " + loc.Name)
+	}
+	return nil
+}
+
+func commandLocation(conf *config, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: location <name>")
+	}
+	loc, err := fetchLocation(conf, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(loc.Name + " in " + loc.Region.Name)
+	fmt.Println("Areas: " + strconv.Itoa(len(loc.Areas)) + " (use areas " + loc.Name + " to list them)")
+	return nil
+}
+
+func commandAreas(conf *config, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: areas <location>")
+	}
+	loc, err := fetchLocation(conf, args[0])
+	if err != nil {
+		return err
+	}
+	if len(loc.Areas) == 0 {
+		fmt.Println(loc.Name + " has no explorable areas")
+		return nil
+	}
+	for _, area := range loc.Areas {
+		fmt.Println(area.Name)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -57,7 +58,10 @@ type locationArea struct {
 			EncounterDetails []struct {
 				MinLevel        int   `json:"min_level"`
 				MaxLevel        int   `json:"max_level"`
-				ConditionValues []any `json:"condition_values"`
+				ConditionValues []struct {
+					Name string `json:"name"`
+					URL  string `json:"url"`
+				} `json:"condition_values"`
 				Chance          int   `json:"chance"`
 				Method          struct {
 					Name string `json:"name"`
@@ -343,7 +347,7 @@ type Pokemon struct {
 type resourceList struct {
 	Count    int    `json:"count"`
 	Next     string `json:"next"`
-	Previous any    `json:"previous"`
+	Previous string `json:"previous"`
 	Results  []struct {
 		Name string `json:"name"`
 		URL  string `json:"url"`
@@ -358,35 +362,57 @@ type result struct {
 type cliCommand struct {
 	name        string
 	description string
-	callback    func(*config, string) error
+	callback    func(*config, ...string) error
 }
 
 type config struct {
-	index   	int
-	offset		int
-	next    	string
-	previous 	string
+	areaPages	*paginator
 	cache	 	pokecache.Cache
-	pokedex		map[string]Pokemon
+	pokedex		map[int]*caughtPokemon
+	nextID		int
+	scanner		*bufio.Scanner
+	rng			*rand.Rand
+	trainer		*trainer
+	location	*locationArea
+	encounter	*wildEncounter
+	version		string
+	versionGroup	string
+	language	string
+	typeChart	typeChart
+	battle		*battle
+	shinyOdds	int
+	profilePath	string
 }
 
-const locationAreaListURL = "https://pokeapi.co/api/v2/location-area/?offset="
 const locationAreaURL = 	"https://pokeapi.co/api/v2/location-area/"
 const pokemonURL =			"https://pokeapi.co/api/v2/pokemon/"
+const pokemonSpeciesURL =	"https://pokeapi.co/api/v2/pokemon-species/"
+const versionURL =			"https://pokeapi.co/api/v2/version/"
+const languageURL =			"https://pokeapi.co/api/v2/language/"
+const typeURL =				"https://pokeapi.co/api/v2/type/"
+const moveURL =				"https://pokeapi.co/api/v2/move/"
+const abilityURL =			"https://pokeapi.co/api/v2/ability/"
+const itemURL =				"https://pokeapi.co/api/v2/item/"
+const regionURL =			"https://pokeapi.co/api/v2/region/"
+const locationURL =			"https://pokeapi.co/api/v2/location/"
 
 func main() {
 	scanner := bufio.NewScanner(os.Stdin)
 	commands := cliCommands()
 	cache := pokecache.NewCache(time.Duration(time.Second * 5))
 	pokeConfig := config{
-		index:    	0,
-		offset:	  	20,
-		next:     	"",
-		previous: 	"",
+		areaPages:	newPaginator(locationAreaURL, 20),
 		cache:		cache,
-		pokedex:	map[string]Pokemon{},
+		pokedex:	map[int]*caughtPokemon{},
+		nextID:		1,
+		scanner:	scanner,
+		rng:		rand.New(rand.NewSource(time.Now().UnixNano())),
+		trainer:	newTrainer(),
+		language:	"en",
+		shinyOdds:	defaultShinyOdds,
+		profilePath:	defaultProfilePath(),
 	}
-	updateConf(&pokeConfig)
+	openProfile(&pokeConfig)
 	for {
 		fmt.Print("Pokedex > ")
 		if !scanner.Scan() {
@@ -397,18 +423,16 @@ func main() {
 			// TODO: Implement command error handling
 		} else {
 			command := scanner.Text()
-			args := strings.Split(command, " ")
+			args := strings.Fields(command)
+			if len(args) == 0 {
+				continue
+			}
 			function, ok := commands[args[0]]
 			if !ok {
 				fmt.Println("unknown command: " + command)
 				continue
 			}
-			var err error
-			if len(args) == 1 {
-				err = function.callback(&pokeConfig, "")
-			} else {
-				err = function.callback(&pokeConfig, args[1])
-			}
+			err := function.callback(&pokeConfig, args[1:]...)
 			if err != nil {
 				fmt.Println(err)
 				continue
@@ -427,29 +451,214 @@ func cliCommands() map[string]cliCommand {
 		},
 		"exit": {
 			name:        "exit",
-			description: "Exit the Pokedex",
+			description: "Save your profile and exit the Pokedex",
 			callback:    commandExit,
 		},
 		"map": {
 			name:        "map",
-			description: "Retrieve the next 20 location areas",
+			description: "Retrieve the next page of location areas: map [first|last|page <n>]",
 			callback:    commandMap,
 		},
 		"mapb": {
 			name:        "mapb",
-			description: "Retrieve the previous 20 location areas",
+			description: "Retrieve the previous page of location areas",
 			callback:    commandMapb,
 		},
+		"regions": {
+			name:        "regions",
+			description: "List all regions",
+			callback:    commandRegions,
+		},
+		"region": {
+			name:        "region",
+			description: "List the locations in a region: region <name>",
+			callback:    commandRegion,
+		},
+		"location": {
+			name:        "location",
+			description: "Show a location and its region: location <name>",
+			callback:    commandLocation,
+		},
+		"areas": {
+			name:        "areas",
+			description: "List the explorable areas of a location: areas <location>",
+			callback:    commandAreas,
+		},
+		"list": {
+			name:        "list",
+			description: "List every entry of a resource such as pokemon, item, move, berry or type: list <resource> [--limit <n>]",
+			callback:    commandList,
+		},
+		"search": {
+			name:        "search",
+			description: "Find pokemon and location area names close to a term: search <term>",
+			callback:    commandSearch,
+		},
+		"pagesize": {
+			name:        "pagesize",
+			description: "Show or set how many location areas map lists per page: pagesize [<n>]",
+			callback:    commandPageSize,
+		},
 		"explore": {
 			name:        "explore",
-			description: "Retrieve a list of pokemon within a location area",
+			description: "Retrieve a list of pokemon within a location area: explore <location-area> [--detailed] [--sort rarity|common]",
 			callback:    commandExplore,
 		},
+		"version": {
+			name:        "version",
+			description: "Show or select the game version data is filtered by: version [<name>|all]",
+			callback:    commandVersion,
+		},
+		"language": {
+			name:        "language",
+			description: "Show or select the language for names and descriptions: language [<code>]",
+			callback:    commandLanguage,
+		},
+		"species": {
+			name:        "species",
+			description: "Show species information such as genus, habitat and flavor text: species <name>",
+			callback:    commandSpecies,
+		},
+		"evolution": {
+			name:        "evolution",
+			description: "Show the evolution tree of a species: evolution <name>",
+			callback:    commandEvolution,
+		},
+		"evolve": {
+			name:        "evolve",
+			description: "Evolve a caught pokemon that meets its evolution conditions: evolve <id> [<species>] [--trade]",
+			callback:    commandEvolve,
+		},
+		"weakness": {
+			name:        "weakness",
+			description: "Show how every attacking type fares against a pokemon: weakness <pokemon>",
+			callback:    commandWeakness,
+		},
+		"matchup": {
+			name:        "matchup",
+			description: "Show the damage multiplier of a type against a pokemon: matchup <attacker-type> <pokemon>",
+			callback:    commandMatchup,
+		},
+		"team": {
+			name:        "team",
+			description: "Analyze weaknesses, coverage and stats of your party or chosen pokemon: team analyze [<id>...]",
+			callback:    commandTeam,
+		},
+		"party": {
+			name:        "party",
+			description: "Show or rearrange your party of six: party [add <id>|remove <id>|swap <id> <id>]",
+			callback:    commandParty,
+		},
+		"box": {
+			name:        "box",
+			description: "Show your PC boxes or the pokemon in one of them: box [<n>]",
+			callback:    commandBox,
+		},
+		"battle": {
+			name:        "battle",
+			description: "Battle the current wild pokemon with your lead party pokemon",
+			callback:    commandBattle,
+		},
+		"fight": {
+			name:        "fight",
+			description: "Use a move in the current battle: fight <move>",
+			callback:    commandFight,
+		},
+		"run": {
+			name:        "run",
+			description: "Run from the current battle, leaving the wild pokemon weakened",
+			callback:    commandRun,
+		},
+		"moves": {
+			name:        "moves",
+			description: "List the moves a pokemon learns: moves <pokemon> [--method level-up|machine|egg|tutor] [--version-group <name>]",
+			callback:    commandMoves,
+		},
+		"move": {
+			name:        "move",
+			description: "Show a move's power, accuracy, PP, type and effect: move <name>",
+			callback:    commandMove,
+		},
+		"teach": {
+			name:        "teach",
+			description: "Teach a caught pokemon a move from its learnset: teach <id> <move>",
+			callback:    commandTeach,
+		},
+		"forget": {
+			name:        "forget",
+			description: "Make a caught pokemon forget the move in a slot: forget <id> <slot>",
+			callback:    commandForget,
+		},
+		"ability": {
+			name:        "ability",
+			description: "Show an ability's effect and the pokemon that have it: ability <name>",
+			callback:    commandAbility,
+		},
+		"item": {
+			name:        "item",
+			description: "Show an item's category, cost and effect: item <name>",
+			callback:    commandItem,
+		},
+		"walk": {
+			name:        "walk",
+			description: "Walk through the tall grass of the current area",
+			callback:    commandWalk,
+		},
+		"surf": {
+			name:        "surf",
+			description: "Surf the water of the current area",
+			callback:    commandSurf,
+		},
+		"fish": {
+			name:        "fish",
+			description: "Fish in the current area: fish [old|good|super]",
+			callback:    commandFish,
+		},
+		"goto": {
+			name:        "goto",
+			description: "Travel to a location area without listing its pokemon: goto <location-area>",
+			callback:    commandGoto,
+		},
 		"catch": {
 			name:        "catch",
-			description: "Attempt to catch a pokemon",
+			description: "Attempt to catch a pokemon in the current area: catch <pokemon> [--ball <kind>]",
 			callback:    commandCatch,
 		},
+		"balls": {
+			name:        "balls",
+			description: "List the balls in your inventory",
+			callback:    commandBalls,
+		},
+		"bag": {
+			name:        "bag",
+			description: "List the items in your bag: bag [balls|medicine|berries|evolution|tms|items]",
+			callback:    commandBag,
+		},
+		"use": {
+			name:        "use",
+			description: "Use an item from your bag on a caught pokemon: use <item> <id|name>",
+			callback:    commandUse,
+		},
+		"give": {
+			name:        "give",
+			description: "Give a caught pokemon an item to hold: give <item> <id|name>",
+			callback:    commandGive,
+		},
+		"sprite": {
+			name:        "sprite",
+			description: "Draw a pokemon's sprite in the terminal: sprite <pokemon|id> [--shiny] [--back] [--gen i-vii] [--colors truecolor|256|ascii]",
+			callback:    commandSprite,
+		},
+		"shinyodds": {
+			name:        "shinyodds",
+			description: "Show or set the one-in-n chance that a catch is shiny: shinyodds [<n>]",
+			callback:    commandShinyOdds,
+		},
+		"save": {
+			name:        "save",
+			description: "Save your pokemon, party, boxes and bag to your profile",
+			callback:    commandSave,
+		},
 		"inspect": {
 			name:        "inspect",
 			description: "Inspect a pokemon you have caught",
@@ -457,13 +666,28 @@ func cliCommands() map[string]cliCommand {
 		},
 		"pokedex": {
 			name:        "pokedex",
-			description: "List all pokemon in your pokedex",
-			callback:    commandPokedex,	
+			description: "List your caught pokemon: pokedex [--sort id|name|nickname|favorite] [--favorites] [--nicknamed]",
+			callback:    commandPokedex,
+		},
+		"release": {
+			name:        "release",
+			description: "Release a caught pokemon: release <id|name>",
+			callback:    commandRelease,
+		},
+		"nickname": {
+			name:        "nickname",
+			description: "Give a caught pokemon a nickname: nickname <id> <name>",
+			callback:    commandNickname,
+		},
+		"favorite": {
+			name:        "favorite",
+			description: "Toggle a caught pokemon as a favorite: favorite <id>",
+			callback:    commandFavorite,
 		},
 	}
 }
 
-func commandHelp(conf *config, arg1 string) error {
+func commandHelp(conf *config, args ...string) error {
 	outStr := "Welcome to the Pokedex!\nUsage:\n\n"
 	commands := cliCommands()
 	for command := range commands {
@@ -473,147 +697,91 @@ func commandHelp(conf *config, arg1 string) error {
 	return nil
 }
 
-func commandExit(conf *config, arg1 string) error {
+func commandExit(conf *config, args ...string) error {
+	autosave(conf)
 	outStr := "Closing the Pokedex... Goodbye!\n"
 	fmt.Println(outStr)
 	os.Exit(0)
 	return nil
 }
 
-func commandMap(conf *config, arg1 string) error {
-	val, exists := conf.cache.Get(conf.next)
-	if exists {
-		location_areas, err := getResults(val)
-		if err != nil {
-			return fmt.Errorf("result list retrieval failed: %s", err)
-		}
-		listResultNames(location_areas)
-	} else {
-		data, err := pokeapi.PokeapiGet(conf.next)
-		if err != nil {
-			return fmt.Errorf("pokeapi get failed: %s", err)
-		}
-		conf.cache.Add(conf.next, data)
-		location_areas, err := getResults(data)
-		if err != nil {
-			return fmt.Errorf("location area retrieval failed: %s", err)
-		}
-		listResultNames(location_areas)
-	}
-	
-	conf.index += conf.offset
-	updateConf(conf)
-	return nil
-}
-
-func commandMapb(conf *config, arg1 string) error {
-	if conf.index <= conf.offset {
-		outStr := "You're on the first page"
-		fmt.Println(outStr)
-		return nil
+func commandMap(conf *config, args ...string) error {
+	if len(args) == 0 {
+		return conf.areaPages.nextPage(conf)
 	}
-	val, exists := conf.cache.Get(conf.previous)
-	if exists {
-		location_areas, err := getResults(val)
-		if err != nil {
-			return fmt.Errorf("result list retrieval failed: %s", err)
+	switch args[0] {
+	case "first":
+		return conf.areaPages.goTo(conf, 1)
+	case "last":
+		return conf.areaPages.lastPage(conf)
+	case "page":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: map page <n>")
 		}
-		listResultNames(location_areas)
-	} else {
-		data, err := pokeapi.PokeapiGet(conf.previous)
+		page, err := strconv.Atoi(args[1])
 		if err != nil {
-			return fmt.Errorf("pokeapi get failed: %s", err)
+			return fmt.Errorf("invalid page: %s", args[1])
 		}
-		conf.cache.Add(conf.previous, data)
-		location_areas, err := getResults(data)
-		if err != nil {
-			return fmt.Errorf("location area retrieval failed: %s", err)
-		}
-		listResultNames(location_areas)
+		return conf.areaPages.goTo(conf, page)
+	default:
+		return fmt.Errorf("usage: map [first|last|page <n>]")
 	}
-	conf.index -= conf.offset
-	updateConf(conf)
-	return nil
 }
 
-func commandExplore(conf *config, arg1 string) error {
-	url := locationAreaURL + arg1
-	val, exists := conf.cache.Get(url)
-	if exists {
-		location_area, err := getLocationArea(val)
-		if err != nil {
-			return fmt.Errorf("result list retrieval failed: %s", err)
-		}
-		listPokemonInLocationArea(location_area)
-	} else {
-		data, err := pokeapi.PokeapiGet(url)
-		if err != nil {
-			return fmt.Errorf("pokeapi get failed: %s", err)
-		}
-		conf.cache.Add(conf.next, data)
-		location_area, err := getLocationArea(data)
-		if err != nil {
-			return fmt.Errorf("location area retrieval failed: %s", err)
-		}
-		listPokemonInLocationArea(location_area)
-	}
-	
-	conf.index += conf.offset
-	updateConf(conf)
-	return nil
+func commandMapb(conf *config, args ...string) error {
+	return conf.areaPages.previousPage(conf)
 }
 
-func commandCatch(conf *config, arg1 string) error {
-	url := pokemonURL + arg1
-	data, err := pokeapi.PokeapiGet(url)
-	if err != nil {
-		return fmt.Errorf("pokeapi get failed: %s", err)
+func commandExplore(conf *config, args ...string) error {
+	positional, flags := parseFlags(args, "detailed")
+	if len(positional) == 0 {
+		return fmt.Errorf("usage: explore <location-area> [--detailed] [--sort rarity|common]")
 	}
-	pokemon, err := getPokemon(data)
-	if err != nil {
-		return fmt.Errorf("pokemon retrieval failed")
+	_, detailed := flags["detailed"]
+	sortBy, sorted := flags["sort"]
+	if sorted && !detailed {
+		return fmt.Errorf("--sort only applies to --detailed output")
 	}
-
-	exp := pokemon.BaseExperience
-
-	var randInt int
-	if exp > 350 {
-		randInt = rand.Intn(700)
-	} else if exp > 150 {
-		randInt = rand.Intn(400)
-	} else if exp > 50 {
-		randInt = rand.Intn(200)
-	} else {
-		randInt = rand.Intn(100)
-
+	if sorted && sortBy == "" {
+		return fmt.Errorf("usage: explore <location-area> [--detailed] [--sort rarity|common]")
 	}
-
-	fmt.Println("Throwing a Pokeball at " + arg1 + "...")
-	if randInt > exp {
-		fmt.Println(arg1 + " was caught!")
-		conf.pokedex[arg1] = pokemon
-	} else {
-		fmt.Println(arg1 + " escaped!")
+	less, err := slotOrder(sortBy)
+	if err != nil {
+		return err
 	}
-
-	return err
-}
-
-func commandInspect(conf *config, arg1 string) error {
-	pokemon, ok := conf.pokedex[arg1]
-	if !ok {
-		fmt.Println("you have not caught that pokemon")
+	location_area, err := fetchLocationArea(conf, positional[0])
+	if err != nil {
+		return err
+	}
+	conf.location = &location_area
+	setEncounter(conf, nil)
+	fmt.Println("Exploring " + location_area.Name + " (" + location_area.Location.Name + ")...")
+	if detailed {
+		printEncounterTables(location_area, conf.version, less)
 	} else {
-		printPokemonStats(pokemon)	
+		listPokemonInLocationArea(location_area, conf.version)
 	}
 	return nil
 }
 
-func commandPokedex(conf *config, arg1 string) error {
-	fmt.Println("Your Pokedex:")
-	for pokemonName := range conf.pokedex {
-		fmt.Println(" - " + pokemonName)
+func commandInspect(conf *config, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: inspect <id|name>")
 	}
+	caught, err := findCaught(conf, args[0])
+	if err != nil {
+		return err
+	}
+	printCaughtDetails(caught)
+	printSprite(caught, conf.version)
+	printPokemonStats(caught.Pokemon)
+	printAbilities(caught.Pokemon)
+	printWildHeldItems(caught.Pokemon, conf.version)
+	printComputedStats(caught)
+	if conf.version != "" {
+		printVersionDetails(caught.Pokemon, conf.version, conf.versionGroup)
+	}
+	printHistory(caught)
 	return nil
 }
 
@@ -623,19 +791,28 @@ func listResultNames(location_areas []result) {
 	}
 }
 
-func listPokemonInLocationArea(location_area locationArea) {
+func listPokemonInLocationArea(location_area locationArea, version string) {
 	for _, encounter := range location_area.PokemonEncounters {
-		fmt.Println(encounter.Pokemon.Name)
+		for _, versionDetail := range encounter.VersionDetails {
+			if inVersion(version, versionDetail.Version.Name) {
+				fmt.Println(encounter.Pokemon.Name)
+				break
+			}
+		}
 	}
 }
 
-func updateConf(conf *config) {
-	conf.next = locationAreaListURL + strconv.Itoa(conf.index)
-	if conf.index >= conf.offset {
-		conf.previous = locationAreaListURL + strconv.Itoa(conf.index - (conf.offset * 2))
-	} else {
-		conf.previous = "N/A: 0 index"
+// fetch returns the body at url, serving it from the cache when possible.
+func fetch(conf *config, url string) ([]byte, error) {
+	if val, exists := conf.cache.Get(url); exists {
+		return val, nil
 	}
+	data, err := pokeapi.PokeapiGet(url)
+	if err != nil {
+		return nil, fmt.Errorf("pokeapi get failed: %w", err)
+	}
+	conf.cache.Add(url, data)
+	return data, nil
 }
 
 func getResourceList(data []byte) (resourceList, error) {
@@ -681,6 +858,18 @@ func getPokemon(data []byte) (Pokemon, error) {
 	return pokemon, nil
 }
 
+func fetchPokemon(conf *config, name string) (Pokemon, error) {
+	data, err := fetch(conf, pokemonURL+name)
+	if err != nil {
+		return Pokemon{}, err
+	}
+	pokemon, err := getPokemon(data)
+	if err != nil {
+		return Pokemon{}, fmt.Errorf("pokemon retrieval failed")
+	}
+	return pokemon, nil
+}
+
 func printPokemonStats(pokemon Pokemon) {
 	pokeStats := map[string]int{}
 	for _, stat := range pokemon.Stats {
@@ -702,4 +891,25 @@ func printPokemonStats(pokemon Pokemon) {
 	for _, pokeType := range pokemon.Types {
 		fmt.Println("	- " + pokeType.Type.Name)
 	}
-}
\ No newline at end of file
+}
+
+// parseFlags splits command arguments into positional arguments and
+// "--name value" flags. Flags listed in boolFlags never take a value.
+func parseFlags(args []string, boolFlags ...string) ([]string, map[string]string) {
+	positional := []string{}
+	flags := map[string]string{}
+	for i := 0; i < len(args); i++ {
+		if !strings.HasPrefix(args[i], "--") {
+			positional = append(positional, args[i])
+			continue
+		}
+		name := strings.TrimPrefix(args[i], "--")
+		if slices.Contains(boolFlags, name) || i+1 >= len(args) || strings.HasPrefix(args[i+1], "--") {
+			flags[name] = ""
+			continue
+		}
+		flags[name] = args[i+1]
+		i++
+	}
+	return positional, flags
+}
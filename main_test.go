@@ -1 +1,1252 @@
-package main
\ No newline at end of file
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jamistoso/pokedexcli/internal/pokeapi"
+	"github.com/jamistoso/pokedexcli/internal/pokecache"
+)
+
+func TestParseFlags(t *testing.T) {
+	cases := []struct {
+		args       []string
+		positional []string
+		flags      map[string]string
+	}{
+		{
+			args:       []string{"pikachu", "--ball", "great"},
+			positional: []string{"pikachu"},
+			flags:      map[string]string{"ball": "great"},
+		},
+		{
+			args:       []string{"--favorites", "--sort", "name"},
+			positional: []string{},
+			flags:      map[string]string{"favorites": "", "sort": "name"},
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			positional, flags := parseFlags(c.args, "favorites")
+			if strings.Join(positional, " ") != strings.Join(c.positional, " ") {
+				t.Errorf("expected positional %v, got %v", c.positional, positional)
+			}
+			if len(flags) != len(c.flags) {
+				t.Errorf("expected flags %v, got %v", c.flags, flags)
+			}
+			for name, val := range c.flags {
+				if flags[name] != val {
+					t.Errorf("expected flag %s=%q, got %q", name, val, flags[name])
+				}
+			}
+		})
+	}
+}
+
+func TestRelease(t *testing.T) {
+	cases := []struct {
+		answer   string
+		released bool
+	}{
+		{answer: "y", released: true},
+		{answer: "n", released: false},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			conf := &config{
+				pokedex: map[int]*caughtPokemon{},
+				nextID:  1,
+				trainer: newTrainer(),
+				scanner: bufio.NewScanner(strings.NewReader(c.answer + "\n")),
+			}
+			addToPokedex(conf, Pokemon{Name: "pikachu"}, 5)
+			if err := commandRelease(conf, "pikachu"); err != nil {
+				t.Errorf("unexpected error: %s", err)
+				return
+			}
+			_, stillCaught := conf.pokedex[1]
+			if stillCaught == c.released {
+				t.Errorf("expected released=%v", c.released)
+			}
+		})
+	}
+}
+
+func TestAttemptCatch(t *testing.T) {
+	cases := []struct {
+		in      catchInput
+		minRate float64
+		maxRate float64
+	}{
+		{
+			in:      catchInput{captureRate: 255, ballBonus: 255},
+			minRate: 1,
+			maxRate: 1,
+		},
+		{
+			in:      catchInput{captureRate: 255},
+			minRate: 0.2,
+			maxRate: 0.6,
+		},
+		{
+			in:      catchInput{captureRate: 3},
+			minRate: 0,
+			maxRate: 0.05,
+		},
+	}
+
+	const attempts = 2000
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			caught := 0
+			for j := 0; j < attempts; j++ {
+				if _, ok := attemptCatch(rng, c.in); ok {
+					caught++
+				}
+			}
+			rate := float64(caught) / attempts
+			if rate < c.minRate || rate > c.maxRate {
+				t.Errorf("expected catch rate in [%v, %v], got %v", c.minRate, c.maxRate, rate)
+			}
+		})
+	}
+}
+
+func TestAttemptCatchSeeded(t *testing.T) {
+	in := catchInput{captureRate: 45}
+	first := rand.New(rand.NewSource(42))
+	second := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		shakesA, caughtA := attemptCatch(first, in)
+		shakesB, caughtB := attemptCatch(second, in)
+		if shakesA != shakesB || caughtA != caughtB {
+			t.Errorf("expected identical outcomes for identical seeds")
+			return
+		}
+	}
+}
+
+func TestBallModifiers(t *testing.T) {
+	night := time.Date(2024, 12, 1, 22, 0, 0, 0, time.UTC)
+	noon := time.Date(2024, 12, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		kind     string
+		ctx      ballContext
+		expected float64
+	}{
+		{kind: "great", ctx: ballContext{now: noon}, expected: 1.5},
+		{kind: "net", ctx: ballContext{types: []string{"water"}, now: noon}, expected: 3.5},
+		{kind: "net", ctx: ballContext{types: []string{"fire"}, now: noon}, expected: 1},
+		{kind: "dusk", ctx: ballContext{now: night}, expected: 3},
+		{kind: "dusk", ctx: ballContext{now: noon}, expected: 1},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if got := pokeBalls[c.kind].modifier(c.ctx); got != c.expected {
+				t.Errorf("expected %v, got %v", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestTakeBallRunsOut(t *testing.T) {
+	trainer := newTrainer()
+	if _, err := trainer.takeBall("master"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if _, err := trainer.takeBall("master"); err == nil {
+		t.Errorf("expected to be out of master balls")
+	}
+}
+
+const testAreaJSON = `{
+	"name": "viridian-forest-area",
+	"pokemon_encounters": [
+		{
+			"pokemon": {"name": "pikachu"},
+			"version_details": [
+				{
+					"version": {"name": "red"},
+					"max_chance": 100,
+					"encounter_details": [
+						{"min_level": 3, "max_level": 5, "chance": 5, "method": {"name": "walk"}, "condition_values": [{"name": "time-morning"}]}
+					]
+				}
+			]
+		}
+	]
+}`
+
+func TestRollSpeciesEncounter(t *testing.T) {
+	area, err := getLocationArea([]byte(testAreaJSON))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	rng := rand.New(rand.NewSource(1))
+
+	encounter, found, err := rollSpeciesEncounter(rng, area, "red", "pikachu")
+	if err != nil || !found {
+		t.Errorf("expected to encounter pikachu")
+		return
+	}
+	if encounter.level < 3 || encounter.level > 5 {
+		t.Errorf("expected level between 3 and 5, got %d", encounter.level)
+	}
+
+	if _, _, err := rollSpeciesEncounter(rng, area, "", "mewtwo"); err == nil {
+		t.Errorf("expected mewtwo to be missing from the area")
+	}
+	if _, _, err := rollSpeciesEncounter(rng, area, "blue", "pikachu"); err == nil {
+		t.Errorf("expected pikachu to be missing from blue")
+	}
+}
+
+func TestRollWildEncounter(t *testing.T) {
+	area, err := getLocationArea([]byte(testAreaJSON))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	rng := rand.New(rand.NewSource(1))
+
+	encounter, err := rollWildEncounter(rng, area, "", "walk")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if encounter.pokemon != "pikachu" {
+		t.Errorf("expected pikachu, got %s", encounter.pokemon)
+	}
+
+	if _, err := rollWildEncounter(rng, area, "", "surf"); err == nil {
+		t.Errorf("expected no surf encounters")
+	}
+	if _, err := rollWildEncounter(rng, area, "blue", "walk"); err == nil {
+		t.Errorf("expected no encounters in another version")
+	}
+}
+
+func TestEncounterSlotConditions(t *testing.T) {
+	area, err := getLocationArea([]byte(testAreaJSON))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	slots := encounterSlots(area)
+	if len(slots) != 1 {
+		t.Errorf("expected 1 slot, got %d", len(slots))
+		return
+	}
+	if strings.Join(slots[0].conditions, ",") != "time-morning" {
+		t.Errorf("expected time-morning condition, got %v", slots[0].conditions)
+	}
+}
+
+func TestPaginator(t *testing.T) {
+	conf := &config{cache: pokecache.NewCache(time.Minute)}
+	pages := newPaginator("https://example.com/area/", 2)
+	for page, body := range map[int]string{
+		1: `{"count": 5, "next": "` + pages.pageURL(2) + `", "previous": null, "results": [{"name": "a"}, {"name": "b"}]}`,
+		2: `{"count": 5, "next": "` + pages.pageURL(3) + `", "previous": "` + pages.pageURL(1) + `", "results": [{"name": "c"}, {"name": "d"}]}`,
+		3: `{"count": 5, "next": null, "previous": "` + pages.pageURL(2) + `", "results": [{"name": "e"}]}`,
+	} {
+		conf.cache.Add(pages.pageURL(page), []byte(body))
+	}
+
+	steps := []struct {
+		action func() error
+		page   int
+	}{
+		{action: func() error { return pages.nextPage(conf) }, page: 1},
+		{action: func() error { return pages.nextPage(conf) }, page: 2},
+		{action: func() error { return pages.lastPage(conf) }, page: 3},
+		{action: func() error { return pages.nextPage(conf) }, page: 3},
+		{action: func() error { return pages.previousPage(conf) }, page: 2},
+		{action: func() error { return pages.goTo(conf, 1) }, page: 1},
+		{action: func() error { return pages.previousPage(conf) }, page: 1},
+	}
+	for i, step := range steps {
+		if err := step.action(); err != nil {
+			t.Errorf("step %d: unexpected error: %s", i, err)
+			return
+		}
+		if pages.page != step.page {
+			t.Errorf("step %d: expected page %d, got %d", i, step.page, pages.page)
+			return
+		}
+	}
+	if pages.totalPages() != 3 {
+		t.Errorf("expected 3 pages, got %d", pages.totalPages())
+	}
+	if err := pages.goTo(conf, 4); err == nil {
+		t.Errorf("expected page 4 to be out of range")
+	}
+}
+
+func TestFuzzyMatches(t *testing.T) {
+	names := []string{"pikachu", "raichu", "pichu", "canalave-city-area", "eterna-city-west-gate", "mr-mime-galar", "mr-mine"}
+	cases := []struct {
+		term     string
+		expected string
+	}{
+		{term: "pikachuu", expected: "pikachu"},
+		{term: "canalave-city", expected: "canalave-city-area"},
+		{term: "chu", expected: "pichu"},
+		{term: "mr-mime", expected: "mr-mime-galar"},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			matches := fuzzyMatches(names, c.term, 3)
+			if len(matches) == 0 || matches[0] != c.expected {
+				t.Errorf("expected %s first, got %v", c.expected, matches)
+			}
+		})
+	}
+
+	if d := levenshtein("kitten", "sitting"); d != 3 {
+		t.Errorf("expected distance 3, got %d", d)
+	}
+}
+
+func TestSpeciesText(t *testing.T) {
+	species, err := getPokemonSpecies([]byte(`{
+		"name": "pikachu",
+		"gender_rate": 4,
+		"genera": [{"genus": "Mouse Pokémon", "language": {"name": "en"}}],
+		"flavor_text_entries": [
+			{"flavor_text": "When several of\nthese POKéMON", "language": {"name": "en"}, "version": {"name": "red"}},
+			{"flavor_text": "It keeps its tail\fraised", "language": {"name": "en"}, "version": {"name": "gold"}}
+		]
+	}`))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if species.genus("en") != "Mouse Pokémon" {
+		t.Errorf("expected genus, got %q", species.genus("en"))
+	}
+	if text := species.flavorText("red", "en"); text != "When several of these POKéMON" {
+		t.Errorf("unexpected red flavor text: %q", text)
+	}
+	if text := species.flavorText("", "en"); text != "It keeps its tail raised" {
+		t.Errorf("unexpected latest flavor text: %q", text)
+	}
+	if ratio := genderRatio(species.GenderRate); ratio != "50% male, 50% female" {
+		t.Errorf("unexpected gender ratio: %s", ratio)
+	}
+}
+
+func TestRenderChain(t *testing.T) {
+	var chain evolutionChain
+	err := json.Unmarshal([]byte(`{"chain": {
+		"species": {"name": "eevee"},
+		"evolves_to": [
+			{"species": {"name": "vaporeon"}, "evolution_details": [{"trigger": {"name": "use-item"}, "item": {"name": "water-stone"}}]},
+			{"species": {"name": "espeon"}, "evolution_details": [{"trigger": {"name": "level-up"}, "min_happiness": 160, "time_of_day": "day"}]}
+		]
+	}}`), &chain)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	expected := []string{
+		"eevee *caught*",
+		"├── vaporeon [use-item: water-stone]",
+		"└── espeon [level-up: friendship 160, during the day]",
+	}
+	lines := renderChain(chain.Chain, map[string]bool{"eevee": true})
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected tree:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestEvolutionTarget(t *testing.T) {
+	var link chainLink
+	err := json.Unmarshal([]byte(`{
+		"species": {"name": "charmander"},
+		"evolves_to": [
+			{"species": {"name": "charmeleon"}, "evolution_details": [{"trigger": {"name": "level-up"}, "min_level": 16}]}
+		]
+	}`), &link)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	ctx := evolveContext{now: time.Now()}
+	cases := []struct {
+		level     int
+		canEvolve bool
+	}{
+		{level: 15, canEvolve: false},
+		{level: 16, canEvolve: true},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			caught := &caughtPokemon{Level: c.level, Pokemon: Pokemon{Name: "charmander"}}
+			next, _, err := evolutionTarget(link, caught, "", ctx)
+			if c.canEvolve && (err != nil || next.Species.Name != "charmeleon") {
+				t.Errorf("expected to evolve into charmeleon, got %v", err)
+			}
+			if !c.canEvolve && err == nil {
+				t.Errorf("expected evolution to be refused")
+			}
+		})
+	}
+}
+
+func TestTypeChartMultiplier(t *testing.T) {
+	chart := typeChart{
+		"electric": {"water": 2, "flying": 2, "ground": 0, "electric": 0.5},
+		"ice":      {"flying": 2, "ground": 2, "dragon": 2},
+	}
+	cases := []struct {
+		attacker  string
+		defenders []string
+		expected  float64
+	}{
+		{attacker: "electric", defenders: []string{"water", "flying"}, expected: 4},
+		{attacker: "electric", defenders: []string{"water", "ground"}, expected: 0},
+		{attacker: "electric", defenders: []string{"electric"}, expected: 0.5},
+		{attacker: "ice", defenders: []string{"fire"}, expected: 1},
+		{attacker: "ice", defenders: []string{"ground", "dragon"}, expected: 4},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if got := chart.multiplier(c.attacker, c.defenders); got != c.expected {
+				t.Errorf("expected %v, got %v", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestTeamCoverage(t *testing.T) {
+	chart := typeChart{
+		"electric": {"water": 2, "flying": 2, "ground": 0},
+		"ice":      {"flying": 2, "ground": 2, "grass": 2, "dragon": 2},
+		"rock":     {"flying": 2, "fire": 2, "ice": 2, "bug": 2},
+	}
+	withTypes := func(types ...string) *caughtPokemon {
+		caught := &caughtPokemon{}
+		json.Unmarshal([]byte(`{"types": [{"type": {"name": "`+strings.Join(types, `"}}, {"type": {"name": "`)+`"}}]}`), &caught.Pokemon)
+		return caught
+	}
+	team := []*caughtPokemon{withTypes("water", "flying"), withTypes("flying"), withTypes("ground")}
+
+	shared := sharedWeaknesses(chart, team)
+	if shared["electric"] != 2 || shared["ice"] != 3 || shared["rock"] != 2 {
+		t.Errorf("unexpected shared weaknesses: %v", shared)
+	}
+
+	uncovered := uncoveredTypes(chart, []string{"electric", "ice"})
+	if slices.Contains(uncovered, "water") || slices.Contains(uncovered, "dragon") || !slices.Contains(uncovered, "fire") {
+		t.Errorf("unexpected uncovered types: %v", uncovered)
+	}
+}
+
+func TestStorage(t *testing.T) {
+	trainer := newTrainer()
+	for id := 1; id <= partySize+boxSize+1; id++ {
+		trainer.store(id)
+	}
+	if len(trainer.party) != partySize {
+		t.Errorf("expected a full party, got %d members", len(trainer.party))
+	}
+	if len(trainer.boxes) != 2 || storageName(trainer, partySize+boxSize+1) != "box 2" {
+		t.Errorf("expected the overflow to open box 2")
+	}
+
+	if err := trainer.withdraw(7); err == nil {
+		t.Errorf("expected withdraw into a full party to fail")
+	}
+	if err := trainer.swap(1, 7); err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if trainer.party[0] != 7 || storageName(trainer, 1) != "box 1" {
+		t.Errorf("expected #7 to take #1's place in the party")
+	}
+	if err := trainer.deposit(7); err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if err := trainer.withdraw(1); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	trainer.unstore(1)
+	if storageName(trainer, 1) != "nowhere" {
+		t.Errorf("expected #1 to be gone")
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	base := map[string]int{"hp": 35, "attack": 55, "defense": 40, "special-attack": 50, "special-defense": 50, "speed": 90}
+	stats := computeStats(base, 50)
+	expected := map[string]int{"hp": 95, "attack": 60, "defense": 45, "special-attack": 55, "special-defense": 55, "speed": 95}
+	for name, val := range expected {
+		if stats[name] != val {
+			t.Errorf("expected %s %d, got %d", name, val, stats[name])
+		}
+	}
+}
+
+func TestBattleTurn(t *testing.T) {
+	chart := typeChart{"electric": {"ground": 0, "water": 2}}
+	thunderbolt := move{Name: "thunderbolt", Power: intPtr(90), Accuracy: intPtr(100), Type: pokeapi.NamedResource{Name: "electric"}, DamageClass: pokeapi.NamedResource{Name: "special"}}
+	tackle := move{Name: "tackle", Power: intPtr(40), Accuracy: intPtr(100), Type: pokeapi.NamedResource{Name: "normal"}, DamageClass: pokeapi.NamedResource{Name: "physical"}}
+	stats := map[string]int{"hp": 100, "attack": 50, "defense": 50, "special-attack": 50, "special-defense": 50, "speed": 50}
+	fast := map[string]int{"hp": 100, "attack": 50, "defense": 50, "special-attack": 50, "special-defense": 50, "speed": 100}
+
+	newBattle := func(wildTypes []string) *battle {
+		return &battle{
+			player:    &combatant{name: "pikachu", level: 20, types: []string{"electric"}, stats: fast, hp: 100, moves: []move{thunderbolt}},
+			wild:      &combatant{name: "wild pokemon", level: 20, types: wildTypes, stats: stats, hp: 100, moves: []move{tackle}},
+			encounter: &wildEncounter{hp: 100, maxHP: 100},
+		}
+	}
+
+	immune := newBattle([]string{"ground"})
+	log := immune.turn(rand.New(rand.NewSource(1)), chart, thunderbolt)
+	if immune.wild.hp != 100 {
+		t.Errorf("expected ground type to take no damage, got %d hp", immune.wild.hp)
+	}
+	if log[0] != "pikachu used thunderbolt!" {
+		t.Errorf("expected the faster pokemon to move first, got %q", log[0])
+	}
+
+	first := newBattle([]string{"water"})
+	second := newBattle([]string{"water"})
+	first.turn(rand.New(rand.NewSource(7)), chart, thunderbolt)
+	second.turn(rand.New(rand.NewSource(7)), chart, thunderbolt)
+	if first.wild.hp >= 100 || first.wild.hp != second.wild.hp || first.player.hp != second.player.hp {
+		t.Errorf("expected identical damage for identical seeds, got %d and %d", first.wild.hp, second.wild.hp)
+	}
+	if first.encounter.hp != first.wild.hp {
+		t.Errorf("expected the encounter to track the wild pokemon's hp")
+	}
+}
+
+func TestCalcStats(t *testing.T) {
+	base := map[string]int{"hp": 108, "attack": 130, "defense": 95, "special-attack": 80, "special-defense": 85, "speed": 102}
+	ivs := map[string]int{"hp": 24, "attack": 12, "defense": 30, "special-attack": 16, "special-defense": 23, "speed": 5}
+	evs := map[string]int{"hp": 74, "attack": 190, "defense": 91, "special-attack": 48, "special-defense": 84, "speed": 23}
+	stats := calcStats(base, ivs, evs, "adamant", 78)
+	expected := map[string]int{"hp": 289, "attack": 278, "defense": 193, "special-attack": 135, "special-defense": 171, "speed": 171}
+	for name, val := range expected {
+		if stats[name] != val {
+			t.Errorf("expected %s %d, got %d", name, val, stats[name])
+		}
+	}
+}
+
+func TestExperienceForLevel(t *testing.T) {
+	cases := []struct {
+		growthRate string
+		expected   int
+	}{
+		{growthRate: "fast", expected: 800000},
+		{growthRate: "medium", expected: 1000000},
+		{growthRate: "medium-slow", expected: 1059860},
+		{growthRate: "slow", expected: 1250000},
+		{growthRate: "slow-then-very-fast", expected: 600000},
+		{growthRate: "fast-then-very-slow", expected: 1640000},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if got := experienceForLevel(c.growthRate, 100); got != c.expected {
+				t.Errorf("expected %d, got %d", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestGainExperience(t *testing.T) {
+	caught := &caughtPokemon{Level: 5, GrowthRate: "medium", Experience: 125}
+	levels := caught.gainExperience(216 - 125)
+	if len(levels) != 1 || caught.Level != 6 {
+		t.Errorf("expected to reach level 6, got %d", caught.Level)
+	}
+
+	var defeated Pokemon
+	json.Unmarshal([]byte(`{"stats": [{"effort": 2, "stat": {"name": "speed"}}]}`), &defeated)
+	caught.EVs = map[string]int{"speed": 251}
+	caught.gainEffort(defeated)
+	if caught.EVs["speed"] != maxStatEVs {
+		t.Errorf("expected speed EVs to cap at %d, got %d", maxStatEVs, caught.EVs["speed"])
+	}
+}
+
+func TestMoveEffect(t *testing.T) {
+	var m move
+	err := json.Unmarshal([]byte(`{
+		"name": "thunderbolt",
+		"effect_chance": 10,
+		"effect_entries": [{"effect": "Inflicts regular damage.  Has a $effect_chance% chance to\nparalyze the target.", "language": {"name": "en"}}]
+	}`), &m)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	expected := "Inflicts regular damage. Has a 10% chance to paralyze the target."
+	if got := m.effect("en"); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestSortLearnset(t *testing.T) {
+	moves := []learnableMove{
+		{name: "thunderbolt", method: "machine"},
+		{name: "thunder-shock", level: 1, method: "level-up"},
+		{name: "agility", method: "egg"},
+		{name: "quick-attack", level: 11, method: "level-up"},
+		{name: "growl", level: 1, method: "level-up"},
+	}
+	sortLearnset(moves)
+	names := []string{}
+	for _, m := range moves {
+		names = append(names, m.name)
+	}
+	expected := "growl thunder-shock quick-attack agility thunderbolt"
+	if strings.Join(names, " ") != expected {
+		t.Errorf("expected %s, got %s", expected, strings.Join(names, " "))
+	}
+}
+
+func TestTeachAndForget(t *testing.T) {
+	var pikachu Pokemon
+	err := json.Unmarshal([]byte(`{"name": "pikachu", "moves": [
+		{"move": {"name": "thunder-shock"}, "version_group_details": [{"level_learned_at": 1, "version_group": {"name": "red-blue"}, "move_learn_method": {"name": "level-up"}}]},
+		{"move": {"name": "growl"}, "version_group_details": [{"level_learned_at": 1, "version_group": {"name": "red-blue"}, "move_learn_method": {"name": "level-up"}}]},
+		{"move": {"name": "thunder"}, "version_group_details": [{"level_learned_at": 43, "version_group": {"name": "red-blue"}, "move_learn_method": {"name": "level-up"}}]},
+		{"move": {"name": "surf"}, "version_group_details": [{"level_learned_at": 0, "version_group": {"name": "yellow"}, "move_learn_method": {"name": "machine"}}]}
+	]}`), &pikachu)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	conf := &config{pokedex: map[int]*caughtPokemon{}, nextID: 1, trainer: newTrainer(), versionGroup: "red-blue"}
+	caught := addToPokedex(conf, pikachu, 10)
+	caught.Moves = defaultMoveset(pikachu, conf.versionGroup, caught.Level)
+	if strings.Join(caught.Moves, ",") != "thunder-shock,growl" {
+		t.Errorf("unexpected default moves: %v", caught.Moves)
+	}
+
+	if err := commandTeach(conf, "1", "thunder"); err == nil {
+		t.Errorf("expected thunder to need level 43")
+	}
+	if err := commandTeach(conf, "1", "surf"); err == nil {
+		t.Errorf("expected surf to be missing from red-blue")
+	}
+	if err := commandForget(conf, "1", "1"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := commandForget(conf, "1", "1"); err == nil {
+		t.Errorf("expected the last move to be kept")
+	}
+	if err := commandTeach(conf, "1", "thunder-shock"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if strings.Join(caught.Moves, ",") != "growl,thunder-shock" {
+		t.Errorf("unexpected moves: %v", caught.Moves)
+	}
+}
+
+func TestItemPocket(t *testing.T) {
+	cases := []struct {
+		name     string
+		category string
+		expected string
+	}{
+		{"great-ball", "standard-balls", "balls"},
+		{"super-potion", "healing", "medicine"},
+		{"rare-candy", "vitamins", "medicine"},
+		{"cheri-berry", "medicine", "berries"},
+		{"fire-stone", "evolution", "evolution"},
+		{"tm01", "all-machines", "tms"},
+		{"nugget", "loot", "items"},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			it := item{Name: c.name, Category: pokeapi.NamedResource{Name: c.category}}
+			if got := itemPocket(it); got != c.expected {
+				t.Errorf("expected %s, got %s", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestRollHeldItem(t *testing.T) {
+	var pokemon Pokemon
+	err := json.Unmarshal([]byte(`{"held_items": [
+		{"item": {"name": "nugget"}, "version_details": [{"rarity": 0, "version": {"name": "red"}}]},
+		{"item": {"name": "oran-berry"}, "version_details": [{"rarity": 100, "version": {"name": "ruby"}}]}
+	]}`), &pokemon)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	rng := rand.New(rand.NewSource(1))
+	if name, ok := rollHeldItem(rng, pokemon, ""); !ok || name != "oran-berry" {
+		t.Errorf("expected oran-berry, got %q", name)
+	}
+	if name, ok := rollHeldItem(rng, pokemon, "red"); ok {
+		t.Errorf("expected nothing in red, got %s", name)
+	}
+}
+
+func TestUseAndGive(t *testing.T) {
+	conf := &config{pokedex: map[int]*caughtPokemon{}, nextID: 1, trainer: newTrainer(), cache: pokecache.NewCache(time.Minute)}
+	conf.cache.Add(itemURL+"potion", []byte(`{"name": "potion", "category": {"name": "healing"}}`))
+	caught := addToPokedex(conf, Pokemon{Name: "pikachu"}, 5)
+	caught.GrowthRate = "medium"
+	caught.Experience = experienceForLevel("medium", 5)
+	conf.trainer.addItem("protein", "medicine", 1)
+	conf.trainer.addItem("rare-candy", "medicine", 1)
+	conf.trainer.addItem("light-ball", "items", 1)
+
+	if err := commandUse(conf, "protein", "1"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if caught.EVs["attack"] != vitaminEVs || conf.trainer.count("protein") != 0 {
+		t.Errorf("expected protein to be used up, got %v EVs", caught.EVs["attack"])
+	}
+	if err := commandUse(conf, "rare-candy", "pikachu"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if caught.Level != 6 {
+		t.Errorf("expected level 6, got %d", caught.Level)
+	}
+	if err := commandUse(conf, "potion", "1"); err != nil || conf.trainer.count("potion") != 5 {
+		t.Errorf("expected the potion to have no effect outside battle")
+	}
+	if err := commandGive(conf, "potion", "1"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := commandGive(conf, "light-ball", "1"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if caught.HeldItem != "light-ball" || conf.trainer.count("light-ball") != 0 || conf.trainer.count("potion") != 5 {
+		t.Errorf("expected the potion back in the bag, holding %s", caught.HeldItem)
+	}
+	if err := commandGive(conf, "light-ball", "1"); err == nil {
+		t.Errorf("expected to be out of light balls")
+	}
+}
+
+func TestRollVariants(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		if gender := rollGender(rng, -1); gender != "genderless" {
+			t.Errorf("expected genderless, got %s", gender)
+		}
+		if gender := rollGender(rng, 0); gender != "male" {
+			t.Errorf("expected male, got %s", gender)
+		}
+		if gender := rollGender(rng, 8); gender != "female" {
+			t.Errorf("expected female, got %s", gender)
+		}
+		if !rollShiny(rng, 1) {
+			t.Errorf("expected odds of 1 to always be shiny")
+		}
+		if rollShiny(rng, 0) {
+			t.Errorf("expected odds of 0 to never be shiny")
+		}
+	}
+}
+
+func TestPickSprite(t *testing.T) {
+	full := spriteSet{
+		FrontDefault:     "front",
+		FrontShiny:       "front-shiny",
+		FrontFemale:      "front-female",
+		FrontShinyFemale: "front-shiny-female",
+		BackDefault:      "back",
+		BackShiny:        "back-shiny",
+	}
+	plain := spriteSet{FrontDefault: "front"}
+	cases := []struct {
+		sprites  spriteSet
+		shiny    bool
+		female   bool
+		back     bool
+		expected string
+	}{
+		{full, false, false, false, "front"},
+		{full, true, false, false, "front-shiny"},
+		{full, false, true, false, "front-female"},
+		{full, true, true, false, "front-shiny-female"},
+		{full, true, true, true, "back-shiny"},
+		{full, false, true, true, "back"},
+		{plain, true, true, false, "front"},
+		{plain, false, false, true, ""},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if got := pickSprite(c.sprites, c.shiny, c.female, c.back); got != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, got)
+			}
+		})
+	}
+}
+
+func loadFixturePNG(t *testing.T, name string) image.Image {
+	t.Helper()
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return img
+}
+
+func TestRenderSprite(t *testing.T) {
+	cases := []struct {
+		fixture  string
+		mode     colorMode
+		expected string
+	}{
+		{"margin.png", colorASCII, "--+\n @ \n"},
+		{"solid.png", colorASCII, "*.\n"},
+		{
+			"margin.png", colorTrueColor,
+			"\x1b[38;2;255;0;0m\x1b[48;2;0;0;255m▀\x1b[0m\x1b[38;2;255;0;0m▀\x1b[38;2;255;255;255m\x1b[48;2;0;0;0m▀\x1b[0m\n" +
+				"\x1b[0m \x1b[0m\x1b[38;2;255;255;255m▀\x1b[0m \x1b[0m\n",
+		},
+		{"solid.png", color256, "\x1b[38;5;231m\x1b[48;5;196m▀\x1b[38;5;16m\x1b[48;5;21m▀\x1b[0m\n"},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if got := renderSprite(loadFixturePNG(t, c.fixture), c.mode); got != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestDetectColorMode(t *testing.T) {
+	cases := []struct {
+		env      map[string]string
+		expected colorMode
+	}{
+		{map[string]string{"COLORTERM": "truecolor", "TERM": "xterm-256color"}, colorTrueColor},
+		{map[string]string{"TERM": "xterm-256color"}, color256},
+		{map[string]string{"TERM": "dumb"}, colorASCII},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			getenv := func(key string) string { return c.env[key] }
+			if got := detectColorMode(getenv); got != c.expected {
+				t.Errorf("expected %v, got %v", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestExploreRejectsBadSort(t *testing.T) {
+	location := &locationArea{Name: "viridian-forest-area"}
+	conf := &config{location: location}
+	cases := [][]string{
+		{"pallet-town-area", "--detailed", "--sort", "alphabetical"},
+		{"pallet-town-area", "--detailed", "--sort"},
+		{"pallet-town-area", "--sort", "rarity"},
+	}
+
+	for i, args := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if err := commandExplore(conf, args...); err == nil {
+				t.Errorf("expected an error for %v", args)
+			}
+			if conf.location != location {
+				t.Errorf("expected the location to be unchanged")
+			}
+		})
+	}
+}
+
+func TestProfileRoundTrip(t *testing.T) {
+	path := t.TempDir() + "/profile.json"
+	conf := &config{pokedex: map[int]*caughtPokemon{}, nextID: 1, trainer: newTrainer(), profilePath: path}
+	for i := 0; i < partySize+2; i++ {
+		addToPokedex(conf, Pokemon{Name: "rattata"}, 3)
+	}
+	conf.pokedex[2].Nickname = "Joey"
+	conf.pokedex[2].Shiny = true
+	if err := conf.trainer.swap(1, 8); err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if _, err := conf.trainer.takeBall("master"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if err := saveProfile(conf); err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if strings.Contains(string(data), "sprites") {
+		t.Errorf("expected the profile to leave out the species data")
+	}
+
+	loaded := &config{pokedex: map[int]*caughtPokemon{}, nextID: 1, trainer: newTrainer(), profilePath: path, cache: pokecache.NewCache(time.Minute)}
+	loaded.cache.Add(pokemonURL+"rattata", []byte(`{"name": "rattata", "base_experience": 51}`))
+	if ok, err := loadProfile(loaded); err != nil || !ok {
+		t.Errorf("expected the profile to load, got %v", err)
+		return
+	}
+	if loaded.nextID != conf.nextID || len(loaded.pokedex) != len(conf.pokedex) {
+		t.Errorf("expected %d pokemon and next id %d, got %d and %d", len(conf.pokedex), conf.nextID, len(loaded.pokedex), loaded.nextID)
+	}
+	if !slices.Equal(loaded.trainer.party, conf.trainer.party) || !slices.Equal(loaded.trainer.boxes, conf.trainer.boxes) {
+		t.Errorf("expected party %v and boxes %v, got %v and %v", conf.trainer.party, conf.trainer.boxes, loaded.trainer.party, loaded.trainer.boxes)
+	}
+	if joey := loaded.pokedex[2]; joey.Nickname != "Joey" || !joey.Shiny || joey.Pokemon.BaseExperience != 51 || len(joey.History) != 1 {
+		t.Errorf("unexpected record: %+v", joey)
+	}
+	if loaded.trainer.count(ballItem("master")) != 0 || loaded.trainer.count(ballItem("poke")) != 20 {
+		t.Errorf("unexpected bag: %v", loaded.trainer.bag)
+	}
+}
+
+func TestLoadMissingProfile(t *testing.T) {
+	conf := &config{pokedex: map[int]*caughtPokemon{}, nextID: 1, trainer: newTrainer(), profilePath: t.TempDir() + "/profile.json"}
+	if ok, err := loadProfile(conf); err != nil || ok {
+		t.Errorf("expected a missing profile to be skipped, got %v, %v", ok, err)
+	}
+}
+
+func TestRunKeepsWildDamage(t *testing.T) {
+	encounter := &wildEncounter{pokemon: "pidgey", level: 3, hp: 4, maxHP: 15}
+	conf := &config{encounter: encounter, battle: &battle{encounter: encounter}}
+	if err := commandRun(conf); err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if conf.battle != nil {
+		t.Errorf("expected the battle to be over")
+	}
+	if conf.encounter != encounter || conf.encounter.hp != 4 {
+		t.Errorf("expected the weakened pidgey to stay, got %+v", conf.encounter)
+	}
+	if err := commandRun(conf); err == nil {
+		t.Errorf("expected an error when not in a battle")
+	}
+}
+
+func TestAbilityLines(t *testing.T) {
+	var pokemon Pokemon
+	err := json.Unmarshal([]byte(`{"abilities": [
+		{"ability": {"name": "static"}, "is_hidden": false, "slot": 1},
+		{"ability": {"name": "lightning-rod"}, "is_hidden": true, "slot": 3}
+	]}`), &pokemon)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	expected := []string{"	- static", "	- lightning-rod (hidden)"}
+	if got := abilityLines(pokemon); !slices.Equal(got, expected) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestWildHeldItemLines(t *testing.T) {
+	var pokemon Pokemon
+	err := json.Unmarshal([]byte(`{"held_items": [
+		{"item": {"name": "oran-berry"}, "version_details": [
+			{"rarity": 50, "version": {"name": "ruby"}},
+			{"rarity": 5, "version": {"name": "sapphire"}}
+		]},
+		{"item": {"name": "light-ball"}, "version_details": [{"rarity": 5, "version": {"name": "yellow"}}]}
+	]}`), &pokemon)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	cases := []struct {
+		version  string
+		expected []string
+	}{
+		{"", []string{"	- oran-berry (50% in ruby, 5% in sapphire)", "	- light-ball (5% in yellow)"}},
+		{"sapphire", []string{"	- oran-berry (5% in sapphire)"}},
+		{"red", []string{}},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if got := wildHeldItemLines(pokemon, c.version); !slices.Equal(got, c.expected) {
+				t.Errorf("expected %q, got %q", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestEffectText(t *testing.T) {
+	var a ability
+	err := json.Unmarshal([]byte(`{"name": "static", "effect_entries": [
+		{"effect": "Hat eine 30% Chance,\nParalyse zu verursachen.", "language": {"name": "de"}},
+		{"effect": "Has a 30% chance of\nparalyzing attacking Pokemon on contact.", "language": {"name": "en"}}
+	]}`), &a)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	cases := []struct {
+		language string
+		expected string
+	}{
+		{"en", "Has a 30% chance of paralyzing attacking Pokemon on contact."},
+		{"de", "Hat eine 30% Chance, Paralyse zu verursachen."},
+		{"fr", ""},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if got := effectText(a.EffectEntries, c.language); got != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestUseMachine(t *testing.T) {
+	var pikachu Pokemon
+	err := json.Unmarshal([]byte(`{"name": "pikachu", "moves": [
+		{"move": {"name": "thunder-shock"}, "version_group_details": [{"level_learned_at": 1, "version_group": {"name": "red-blue"}, "move_learn_method": {"name": "level-up"}}]},
+		{"move": {"name": "thunderbolt"}, "version_group_details": [{"level_learned_at": 0, "version_group": {"name": "red-blue"}, "move_learn_method": {"name": "machine"}}]}
+	]}`), &pikachu)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	conf := &config{pokedex: map[int]*caughtPokemon{}, nextID: 1, trainer: newTrainer(), versionGroup: "red-blue", cache: pokecache.NewCache(time.Minute)}
+	conf.cache.Add(itemURL+"tm24", []byte(`{"name": "tm24", "category": {"name": "all-machines"}, "machines": [
+		{"machine": {"url": "https://pokeapi.co/api/v2/machine/24/"}, "version_group": {"name": "red-blue"}},
+		{"machine": {"url": "https://pokeapi.co/api/v2/machine/99/"}, "version_group": {"name": "gold-silver"}}
+	]}`))
+	conf.cache.Add("https://pokeapi.co/api/v2/machine/24/", []byte(`{"id": 24, "move": {"name": "thunderbolt"}, "version_group": {"name": "red-blue"}}`))
+	caught := addToPokedex(conf, pikachu, 5)
+	caught.Moves = []string{"thunder-shock"}
+	conf.trainer.addItem("tm24", "tms", 1)
+
+	if err := commandUse(conf, "tm24", "1"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if !slices.Equal(caught.Moves, []string{"thunder-shock", "thunderbolt"}) || conf.trainer.count("tm24") != 0 {
+		t.Errorf("expected thunderbolt to be learned and the tm used, got %v", caught.Moves)
+	}
+
+	conf.trainer.addItem("tm24", "tms", 1)
+	if err := commandUse(conf, "tm24", "1"); err == nil {
+		t.Errorf("expected an error teaching a known move")
+	}
+	if conf.trainer.count("tm24") != 1 {
+		t.Errorf("expected the tm to be kept after a failed use")
+	}
+}
+
+func TestVersionFiltering(t *testing.T) {
+	var pokemon Pokemon
+	err := json.Unmarshal([]byte(`{
+		"name": "pikachu",
+		"sprites": {
+			"front_default": "front.png",
+			"versions": {
+				"generation-i": {"red-blue": {"front_default": "rb-front.png", "back_default": "rb-back.png"}},
+				"generation-iv": {"diamond-pearl": {"front_default": "dp-front.png", "front_shiny": "dp-shiny.png", "front_female": "dp-female.png"}}
+			}
+		},
+		"moves": [
+			{"move": {"name": "thunder-shock"}, "version_group_details": [{"version_group": {"name": "red-blue"}}, {"version_group": {"name": "diamond-pearl"}}]},
+			{"move": {"name": "volt-tackle"}, "version_group_details": [{"version_group": {"name": "diamond-pearl"}}]}
+		]
+	}`), &pokemon)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+
+	spriteCases := []struct {
+		version  string
+		expected spriteSet
+		ok       bool
+	}{
+		{"", spriteSet{FrontDefault: "front.png"}, true},
+		{"blue", spriteSet{FrontDefault: "rb-front.png", BackDefault: "rb-back.png"}, true},
+		{"pearl", spriteSet{FrontDefault: "dp-front.png", FrontShiny: "dp-shiny.png", FrontFemale: "dp-female.png"}, true},
+		{"sword", spriteSet{}, false},
+	}
+	for i, c := range spriteCases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			got, ok := versionSprites(pokemon, c.version)
+			if ok != c.ok || got != c.expected {
+				t.Errorf("expected %+v, %v, got %+v, %v", c.expected, c.ok, got, ok)
+			}
+		})
+	}
+
+	moveCases := []struct {
+		versionGroup string
+		expected     []string
+	}{
+		{"", []string{"thunder-shock", "volt-tackle"}},
+		{"red-blue", []string{"thunder-shock"}},
+		{"diamond-pearl", []string{"thunder-shock", "volt-tackle"}},
+		{"sword-shield", []string{}},
+	}
+	for i, c := range moveCases {
+		t.Run(fmt.Sprintf("Test case %v", len(spriteCases)+i), func(t *testing.T) {
+			if got := versionMoves(pokemon, c.versionGroup); !slices.Equal(got, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestRegionLocationAreaChain(t *testing.T) {
+	conf := &config{cache: pokecache.NewCache(time.Minute)}
+	conf.cache.Add(regionURL+"kanto", []byte(`{"id": 1, "name": "kanto", "main_generation": {"name": "generation-i"}, "locations": [
+		{"name": "pallet-town", "url": "https://pokeapi.co/api/v2/location/86/"},
+		{"name": "viridian-forest", "url": "https://pokeapi.co/api/v2/location/321/"}
+	]}`))
+	conf.cache.Add(locationURL+"viridian-forest", []byte(`{"id": 321, "name": "viridian-forest", "region": {"name": "kanto"}, "areas": [
+		{"name": "viridian-forest-area", "url": "https://pokeapi.co/api/v2/location-area/321/"}
+	]}`))
+	conf.cache.Add(locationAreaURL+"viridian-forest-area", []byte(testAreaJSON))
+
+	reg, err := fetchRegion(conf, "kanto")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if reg.MainGeneration.Name != "generation-i" || len(reg.Locations) != 2 || reg.Locations[1].Name != "viridian-forest" {
+		t.Errorf("unexpected region: %+v", reg)
+		return
+	}
+	loc, err := fetchLocation(conf, reg.Locations[1].Name)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if loc.Region.Name != "kanto" || len(loc.Areas) != 1 {
+		t.Errorf("unexpected location: %+v", loc)
+		return
+	}
+	if err := commandGoto(conf, loc.Areas[0].Name); err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if conf.location == nil || conf.location.Name != "viridian-forest-area" {
+		t.Errorf("expected to be in viridian-forest-area, got %+v", conf.location)
+	}
+}
+
+func TestDidYouMeanIgnoresCase(t *testing.T) {
+	conf := &config{cache: pokecache.NewCache(time.Minute)}
+	conf.cache.Add(pokeapi.BaseURL+"pokemon?names", []byte("pikachu\nraichu\npichu"))
+	expected := " (did you mean pikachu?)"
+	if got := didYouMean(conf, "pokemon", "Pikachuu"); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+	if got := didYouMean(conf, "pokemon", "Pikachu"); got != "" {
+		t.Errorf("expected no suggestion for an existing name, got %q", got)
+	}
+}
+
+func TestVariantSpriteFallsBack(t *testing.T) {
+	var pokemon Pokemon
+	err := json.Unmarshal([]byte(`{
+		"sprites": {
+			"front_default": "front.png",
+			"front_shiny": "shiny.png",
+			"back_default": "back.png",
+			"versions": {
+				"generation-i": {"red-blue": {"front_default": "rb-front.png"}},
+				"generation-vii": {"ultra-sun-ultra-moon": {"front_default": "usum-front.png"}}
+			}
+		}
+	}`), &pokemon)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	cases := []struct {
+		version  string
+		shiny    bool
+		back     bool
+		expected string
+	}{
+		{"red", false, false, "rb-front.png"},
+		{"red", false, true, "back.png"},
+		{"moon", false, false, "usum-front.png"},
+		{"sword", false, false, "front.png"},
+		{"scarlet", true, false, "shiny.png"},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if got := variantSprite(pokemon, c.version, c.shiny, false, c.back); got != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestCorruptProfileIsNotOverwritten(t *testing.T) {
+	path := t.TempDir() + "/profile.json"
+	cases := []string{
+		`{"next_id": 3, "pokedex": [`,
+		`{"next_id": 3, "pokedex": [], "party": [7]}`,
+	}
+
+	for i, original := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(original), 0o644); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			conf := &config{pokedex: map[int]*caughtPokemon{}, nextID: 1, trainer: newTrainer(), profilePath: path}
+			openProfile(conf)
+			addToPokedex(conf, Pokemon{Name: "rattata"}, 3)
+			autosave(conf)
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if string(data) != original {
+				t.Errorf("expected the profile to be untouched, got %s", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jamistoso/pokedexcli/internal/pokeapi"
+)
+
+type move struct {
+	ID            int                   `json:"id"`
+	Name          string                `json:"name"`
+	Power         *int                  `json:"power"`
+	Accuracy      *int                  `json:"accuracy"`
+	PP            int                   `json:"pp"`
+	Priority      int                   `json:"priority"`
+	EffectChance  *int                  `json:"effect_chance"`
+	Type          pokeapi.NamedResource `json:"type"`
+	DamageClass   pokeapi.NamedResource `json:"damage_class"`
+	EffectEntries []effectEntry         `json:"effect_entries"`
+}
+
+func fetchMove(conf *config, name string) (move, error) {
+	data, err := fetch(conf, moveURL+name)
+	if err != nil {
+		return move{}, err
+	}
+	var m move
+	if err := json.Unmarshal(data, &m); err != nil {
+		return move{}, fmt.Errorf("move retrieval failed: %s", err)
+	}
+	return m, nil
+}
+
+// isDamaging reports whether the move deals damage directly.
+func (m move) isDamaging() bool {
+	return m.DamageClass.Name != "status" && m.Power != nil && *m.Power > 0
+}
+
+type learnableMove struct {
+	name    string
+	level   int
+	method  string
+	version string
+}
+
+// learnset returns the moves the pokemon learns in a version group, sorted
+// by level. An empty version group includes every game.
+func learnset(pokemon Pokemon, versionGroup string) []learnableMove {
+	moves := []learnableMove{}
+	for _, m := range pokemon.Moves {
+		for _, detail := range m.VersionGroupDetails {
+			if !inVersion(versionGroup, detail.VersionGroup.Name) {
+				continue
+			}
+			moves = append(moves, learnableMove{
+				name:    m.Move.Name,
+				level:   detail.LevelLearnedAt,
+				method:  detail.MoveLearnMethod.Name,
+				version: detail.VersionGroup.Name,
+			})
+		}
+	}
+	sort.SliceStable(moves, func(i, j int) bool { return moves[i].level < moves[j].level })
+	return moves
+}
+
+// levelUpMoves returns the distinct moves learned by leveling up to the
+// given level.
+func levelUpMoves(pokemon Pokemon, versionGroup string, level int) []string {
+	seen := map[string]bool{}
+	names := []string{}
+	for _, m := range learnset(pokemon, versionGroup) {
+		if m.method != "level-up" || m.level > level || seen[m.name] {
+			continue
+		}
+		seen[m.name] = true
+		names = append(names, m.name)
+	}
+	return names
+}
+
+const maxMoves = 4
+
+// knownMoves returns the moves in a caught pokemon's slots. A pokemon
+// without any slots filled knows what a wild one of its level would.
+func knownMoves(caught *caughtPokemon, versionGroup string) []string {
+	if len(caught.Moves) == 0 {
+		return defaultMoveset(caught.Pokemon, versionGroup, caught.Level)
+	}
+	return caught.Moves
+}
+
+// defaultMoveset returns the last four moves learned by leveling up to the
+// given level, the moves a wild pokemon of that level knows.
+func defaultMoveset(pokemon Pokemon, versionGroup string, level int) []string {
+	moves := levelUpMoves(pokemon, versionGroup, level)
+	if len(moves) > maxMoves {
+		moves = moves[len(moves)-maxMoves:]
+	}
+	return moves
+}
+
+func fetchMoves(conf *config, names []string) ([]move, error) {
+	moves := []move{}
+	for _, name := range names {
+		m, err := fetchMove(conf, name)
+		if err != nil {
+			return nil, err
+		}
+		moves = append(moves, m)
+	}
+	return moves, nil
+}
+
+// effect returns the move's effect text in the language, with the effect
+// chance filled in.
+func (m move) effect(language string) string {
+	text := effectText(m.EffectEntries, language)
+	if m.EffectChance != nil {
+		text = strings.ReplaceAll(text, "$effect_chance", strconv.Itoa(*m.EffectChance))
+	}
+	return text
+}
+
+func optionalInt(n *int) string {
+	if n == nil {
+		return "-"
+	}
+	return strconv.Itoa(*n)
+}
+
+// sortLearnset puts level-up moves first by level, then the rest by name.
+func sortLearnset(moves []learnableMove) {
+	sort.SliceStable(moves, func(i, j int) bool {
+		a, b := moves[i], moves[j]
+		if (a.method == "level-up") != (b.method == "level-up") {
+			return a.method == "level-up"
+		}
+		if a.level != b.level {
+			return a.level < b.level
+		}
+		return a.name < b.name
+	})
+}
+
+func commandMoves(conf *config, args ...string) error {
+	positional, flags := parseFlags(args)
+	if len(positional) == 0 {
+		return fmt.Errorf("usage: moves <pokemon> [--method level-up|machine|egg|tutor] [--version-group <name>]")
+	}
+	pokemon, err := fetchPokemon(conf, positional[0])
+	if err != nil {
+		return err
+	}
+	versionGroup := conf.versionGroup
+	if vg, ok := flags["version-group"]; ok {
+		versionGroup = vg
+	}
+	method := flags["method"]
+
+	moves := []learnableMove{}
+	for _, m := range learnset(pokemon, versionGroup) {
+		if method == "" || m.method == method {
+			moves = append(moves, m)
+		}
+	}
+	if len(moves) == 0 {
+		fmt.Println(pokemon.Name + " learns no moves that way")
+		return nil
+	}
+	sortLearnset(moves)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	columns := "LEVEL\tMOVE\tMETHOD"
+	if versionGroup == "" {
+		columns += "\tVERSION GROUP"
+	}
+	fmt.Fprintln(w, columns)
+	for _, m := range moves {
+		level := "-"
+		if m.method == "level-up" {
+			level = strconv.Itoa(m.level)
+		}
+		row := level + "\t" + m.name + "\t" + m.method
+		if versionGroup == "" {
+			row += "\t" + m.version
+		}
+		fmt.Fprintln(w, row)
+	}
+	w.Flush()
+	return nil
+}
+
+func commandMove(conf *config, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: move <name>")
+	}
+	m, err := fetchMove(conf, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(
+		"Name: "+m.Name,
+		"\nType: "+m.Type.Name,
+		"\nDamage class: "+m.DamageClass.Name,
+		"\nPower: "+optionalInt(m.Power),
+		"\nAccuracy: "+optionalInt(m.Accuracy),
+		"\nPP: "+strconv.Itoa(m.PP),
+		"\nPriority: "+strconv.Itoa(m.Priority),
+	)
+	if effect := m.effect(conf.language); effect != "" {
+		fmt.Println()
+		fmt.Println(effect)
+	}
+	return nil
+}
+
+// canLearn checks a move against the pokemon's learnset for the version
+// group. Level-up moves also require the pokemon to have reached the level.
+func canLearn(caught *caughtPokemon, versionGroup string, name string) error {
+	minLevel := -1
+	for _, m := range learnset(caught.Pokemon, versionGroup) {
+		if m.name != name {
+			continue
+		}
+		if m.method != "level-up" {
+			return nil
+		}
+		if minLevel < 0 || m.level < minLevel {
+			minLevel = m.level
+		}
+	}
+	if minLevel < 0 {
+		return fmt.Errorf("%s can't learn %s", caught.Pokemon.Name, name)
+	}
+	if caught.Level < minLevel {
+		return fmt.Errorf("%s learns %s at level %d", caught.Pokemon.Name, name, minLevel)
+	}
+	return nil
+}
+
+func commandTeach(conf *config, args ...string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: teach <id> <move>")
+	}
+	caught, err := findCaughtByID(conf, args[0])
+	if err != nil {
+		return err
+	}
+	return learnMove(caught, conf.versionGroup, args[1])
+}
+
+// learnMove puts a move in a free slot if the pokemon can learn it.
+func learnMove(caught *caughtPokemon, versionGroup string, name string) error {
+	if slices.Contains(caught.Moves, name) {
+		return fmt.Errorf("%s already knows %s", caught.displayName(), name)
+	}
+	if len(caught.Moves) >= maxMoves {
+		return fmt.Errorf("%s already knows %d moves, forget one first", caught.displayName(), maxMoves)
+	}
+	if err := canLearn(caught, versionGroup, name); err != nil {
+		return err
+	}
+	caught.Moves = append(caught.Moves, name)
+	fmt.Println(caught.displayName() + " learned " + name + "!")
+	return nil
+}
+
+func commandForget(conf *config, args ...string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: forget <id> <slot>")
+	}
+	caught, err := findCaughtByID(conf, args[0])
+	if err != nil {
+		return err
+	}
+	slot, err := strconv.Atoi(args[1])
+	if err != nil || slot < 1 || slot > len(caught.Moves) {
+		return fmt.Errorf("%s has no move in slot %s", caught.displayName(), args[1])
+	}
+	if len(caught.Moves) == 1 {
+		return fmt.Errorf("%s can't forget its only move", caught.displayName())
+	}
+	name := caught.Moves[slot-1]
+	caught.Moves = slices.Delete(caught.Moves, slot-1, slot)
+	fmt.Println(caught.displayName() + " forgot " + name)
+	return nil
+}
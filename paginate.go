@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// paginator pages through a PokeAPI resource list. It follows the next and
+// previous links of the last page it showed, and builds offset URLs itself
+// when jumping to an arbitrary page.
+type paginator struct {
+	baseURL  string
+	pageSize int
+	page     int
+	count    int
+	next     string
+	previous string
+}
+
+func newPaginator(baseURL string, pageSize int) *paginator {
+	return &paginator{
+		baseURL:  baseURL,
+		pageSize: pageSize,
+	}
+}
+
+func (p *paginator) pageURL(page int) string {
+	offset := (page - 1) * p.pageSize
+	return p.baseURL + "?offset=" + strconv.Itoa(offset) + "&limit=" + strconv.Itoa(p.pageSize)
+}
+
+// totalPages is only known once a page has been fetched.
+func (p *paginator) totalPages() int {
+	if p.count == 0 {
+		return 0
+	}
+	return (p.count + p.pageSize - 1) / p.pageSize
+}
+
+// setPageSize changes the page size while keeping the first item of the
+// current page on screen.
+func (p *paginator) setPageSize(size int) {
+	if p.page > 0 {
+		offset := (p.page - 1) * p.pageSize
+		p.page = offset/size + 1
+	}
+	p.pageSize = size
+	p.next = ""
+	p.previous = ""
+}
+
+// load fetches a page, prints its names and remembers its position.
+func (p *paginator) load(conf *config, url string, page int) error {
+	data, err := fetch(conf, url)
+	if err != nil {
+		return err
+	}
+	resList, err := getResourceList(data)
+	if err != nil {
+		return fmt.Errorf("result list retrieval failed: %s", err)
+	}
+	if len(resList.Results) == 0 {
+		return fmt.Errorf("page %d is out of range", page)
+	}
+	p.page = page
+	p.count = resList.Count
+	p.next = resList.Next
+	p.previous = resList.Previous
+
+	for _, res := range resList.Results {
+		fmt.Println(res.Name)
+	}
+	fmt.Println("page " + strconv.Itoa(p.page) + " of " + strconv.Itoa(p.totalPages()))
+	return nil
+}
+
+func (p *paginator) nextPage(conf *config) error {
+	if p.page == 0 {
+		return p.load(conf, p.pageURL(1), 1)
+	}
+	if p.next != "" {
+		return p.load(conf, p.next, p.page+1)
+	}
+	if p.page < p.totalPages() {
+		return p.load(conf, p.pageURL(p.page+1), p.page+1)
+	}
+	fmt.Println("You're on the last page")
+	return nil
+}
+
+func (p *paginator) previousPage(conf *config) error {
+	if p.page <= 1 {
+		fmt.Println("You're on the first page")
+		return nil
+	}
+	if p.previous != "" {
+		return p.load(conf, p.previous, p.page-1)
+	}
+	return p.load(conf, p.pageURL(p.page-1), p.page-1)
+}
+
+func (p *paginator) goTo(conf *config, page int) error {
+	if page < 1 {
+		return fmt.Errorf("page numbers start at 1")
+	}
+	if p.count > 0 && page > p.totalPages() {
+		return fmt.Errorf("there are only %d pages", p.totalPages())
+	}
+	return p.load(conf, p.pageURL(page), page)
+}
+
+// lastPage needs the total count, so it looks at the first page first if
+// nothing has been fetched yet.
+func (p *paginator) lastPage(conf *config) error {
+	if p.count == 0 {
+		data, err := fetch(conf, p.pageURL(1))
+		if err != nil {
+			return err
+		}
+		resList, err := getResourceList(data)
+		if err != nil {
+			return fmt.Errorf("result list retrieval failed: %s", err)
+		}
+		p.count = resList.Count
+	}
+	return p.goTo(conf, p.totalPages())
+}
+
+func commandPageSize(conf *config, args ...string) error {
+	if len(args) == 0 {
+		fmt.Println("Page size: " + strconv.Itoa(conf.areaPages.pageSize))
+		return nil
+	}
+	size, err := strconv.Atoi(args[0])
+	if err != nil || size < 1 {
+		return fmt.Errorf("invalid page size: %s", args[0])
+	}
+	conf.areaPages.setPageSize(size)
+	fmt.Println("Page size set to " + strconv.Itoa(size))
+	return nil
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type caughtPokemon struct {
+	ID         int
+	Nickname   string
+	Favorite   bool
+	Shiny      bool
+	Gender     string
+	Level      int
+	Experience int
+	GrowthRate string
+	IVs        map[string]int
+	EVs        map[string]int
+	Nature     string
+	Friendship int
+	Moves      []string
+	HeldItem   string
+	CaughtAt   time.Time
+	CaughtIn   string
+	Pokemon    Pokemon
+	History    []historyEntry
+}
+
+type historyEntry struct {
+	At    time.Time
+	Event string
+}
+
+// displayName returns the nickname if one is set, otherwise the species name.
+func (c *caughtPokemon) displayName() string {
+	if c.Nickname != "" {
+		return c.Nickname
+	}
+	return c.Pokemon.Name
+}
+
+func addToPokedex(conf *config, pokemon Pokemon, level int) *caughtPokemon {
+	caught := &caughtPokemon{
+		ID:       conf.nextID,
+		Level:    level,
+		CaughtAt: time.Now(),
+		Pokemon:  pokemon,
+	}
+	if conf.location != nil {
+		caught.CaughtIn = conf.location.Name
+	}
+	event := "caught at Lv. " + strconv.Itoa(level)
+	if caught.CaughtIn != "" {
+		event += " in " + caught.CaughtIn
+	}
+	caught.record(event)
+	conf.pokedex[caught.ID] = caught
+	conf.trainer.store(caught.ID)
+	conf.nextID++
+	return caught
+}
+
+// record appends an event to the pokemon's history.
+func (c *caughtPokemon) record(event string) {
+	c.History = append(c.History, historyEntry{At: time.Now(), Event: event})
+}
+
+func printCaughtDetails(caught *caughtPokemon) {
+	heldItem := caught.HeldItem
+	if heldItem == "" {
+		heldItem = "nothing"
+	}
+	gender := caught.Gender
+	if gender == "" {
+		gender = "unknown"
+	}
+	fmt.Println(
+		formatCaught(caught),
+		"\nGender: "+gender,
+		"\nFriendship: "+strconv.Itoa(caught.Friendship),
+		"\nHolding: "+heldItem,
+		"\nMoves:",
+	)
+	for i, name := range caught.Moves {
+		fmt.Println("	" + strconv.Itoa(i+1) + ". " + name)
+	}
+}
+
+func printHistory(caught *caughtPokemon) {
+	fmt.Println("History:")
+	for _, entry := range caught.History {
+		fmt.Println("	- " + entry.At.Format("2006-01-02 15:04") + " " + entry.Event)
+	}
+}
+
+// findCaught looks up a caught pokemon by id, or by species name or nickname.
+// A name shared by several caught pokemon is rejected so the caller can
+// fall back to an id.
+func findCaught(conf *config, idOrName string) (*caughtPokemon, error) {
+	if id, err := strconv.Atoi(idOrName); err == nil {
+		caught, ok := conf.pokedex[id]
+		if !ok {
+			return nil, fmt.Errorf("no caught pokemon with id %d", id)
+		}
+		return caught, nil
+	}
+
+	var matches []*caughtPokemon
+	for _, caught := range conf.pokedex {
+		if strings.EqualFold(caught.Pokemon.Name, idOrName) || strings.EqualFold(caught.Nickname, idOrName) {
+			matches = append(matches, caught)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("you have not caught that pokemon")
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("you have %d pokemon named %s, use an id instead", len(matches), idOrName)
+	}
+}
+
+func findCaughtByID(conf *config, arg string) (*caughtPokemon, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id: %s", arg)
+	}
+	caught, ok := conf.pokedex[id]
+	if !ok {
+		return nil, fmt.Errorf("no caught pokemon with id %d", id)
+	}
+	return caught, nil
+}
+
+// confirm asks a yes/no question on the REPL's input and reports whether
+// the answer was yes.
+func confirm(conf *config, question string) bool {
+	fmt.Print(question + " [y/N] ")
+	if !conf.scanner.Scan() {
+		return false
+	}
+	answer := strings.ToLower(strings.TrimSpace(conf.scanner.Text()))
+	return answer == "y" || answer == "yes"
+}
+
+func commandRelease(conf *config, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: release <id|name>")
+	}
+	caught, err := findCaught(conf, args[0])
+	if err != nil {
+		return err
+	}
+	if !confirm(conf, "Release #"+strconv.Itoa(caught.ID)+" "+caught.displayName()+"?") {
+		fmt.Println("Release cancelled")
+		return nil
+	}
+	delete(conf.pokedex, caught.ID)
+	conf.trainer.unstore(caught.ID)
+	fmt.Println(caught.displayName() + " was released. Bye, " + caught.displayName() + "!")
+	return nil
+}
+
+func commandNickname(conf *config, args ...string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: nickname <id> <name>")
+	}
+	caught, err := findCaughtByID(conf, args[0])
+	if err != nil {
+		return err
+	}
+	caught.Nickname = strings.Join(args[1:], " ")
+	fmt.Println(caught.Pokemon.Name + " is now known as " + caught.Nickname)
+	return nil
+}
+
+func commandFavorite(conf *config, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: favorite <id>")
+	}
+	caught, err := findCaughtByID(conf, args[0])
+	if err != nil {
+		return err
+	}
+	caught.Favorite = !caught.Favorite
+	if caught.Favorite {
+		fmt.Println(caught.displayName() + " added to favorites")
+	} else {
+		fmt.Println(caught.displayName() + " removed from favorites")
+	}
+	return nil
+}
+
+func commandPokedex(conf *config, args ...string) error {
+	_, flags := parseFlags(args, "favorites", "nicknamed")
+	sortBy := flags["sort"]
+	if sortBy == "" {
+		sortBy = "id"
+	}
+	_, onlyFavorites := flags["favorites"]
+	_, onlyNicknamed := flags["nicknamed"]
+
+	entries := []*caughtPokemon{}
+	for _, caught := range conf.pokedex {
+		if onlyFavorites && !caught.Favorite {
+			continue
+		}
+		if onlyNicknamed && caught.Nickname == "" {
+			continue
+		}
+		entries = append(entries, caught)
+	}
+	if err := sortCaught(entries, sortBy); err != nil {
+		return err
+	}
+
+	fmt.Println("Your Pokedex:")
+	for _, caught := range entries {
+		fmt.Println(" - " + formatCaught(caught))
+	}
+	return nil
+}
+
+func formatCaught(caught *caughtPokemon) string {
+	line := "#" + strconv.Itoa(caught.ID) + " " + caught.Pokemon.Name + " Lv. " + strconv.Itoa(caught.Level)
+	if caught.Nickname != "" {
+		line += " \"" + caught.Nickname + "\""
+	}
+	if caught.Shiny {
+		line += " (shiny)"
+	}
+	if caught.Favorite {
+		line += " *"
+	}
+	return line
+}
+
+// sortCaught orders entries by the given field, falling back to id so the
+// listing is stable between calls.
+func sortCaught(entries []*caughtPokemon, sortBy string) error {
+	var less func(a, b *caughtPokemon) bool
+	switch sortBy {
+	case "id":
+		less = func(a, b *caughtPokemon) bool { return false }
+	case "name":
+		less = func(a, b *caughtPokemon) bool { return a.Pokemon.Name < b.Pokemon.Name }
+	case "nickname":
+		less = func(a, b *caughtPokemon) bool {
+			if (a.Nickname == "") != (b.Nickname == "") {
+				return a.Nickname != ""
+			}
+			return a.Nickname < b.Nickname
+		}
+	case "favorite":
+		less = func(a, b *caughtPokemon) bool { return a.Favorite && !b.Favorite }
+	default:
+		return fmt.Errorf("unknown sort field: %s", sortBy)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if less(entries[i], entries[j]) {
+			return true
+		}
+		if less(entries[j], entries[i]) {
+			return false
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	return nil
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// profile is the trainer state kept between sessions: every caught
+// pokemon, where each one is stored, and the contents of the bag.
+type profile struct {
+	NextID  int                    `json:"next_id"`
+	Pokedex []savedPokemon         `json:"pokedex"`
+	Party   []int                  `json:"party"`
+	Boxes   []box                  `json:"boxes"`
+	Bag     map[string]profileItem `json:"bag"`
+}
+
+// savedPokemon is what the trainer owns of a caught pokemon. The species
+// data is looked up again by name on load rather than stored.
+type savedPokemon struct {
+	ID         int            `json:"id"`
+	Pokemon    string         `json:"pokemon"`
+	Nickname   string         `json:"nickname,omitempty"`
+	Favorite   bool           `json:"favorite,omitempty"`
+	Shiny      bool           `json:"shiny,omitempty"`
+	Gender     string         `json:"gender,omitempty"`
+	Level      int            `json:"level"`
+	Experience int            `json:"experience"`
+	GrowthRate string         `json:"growth_rate,omitempty"`
+	IVs        map[string]int `json:"ivs,omitempty"`
+	EVs        map[string]int `json:"evs,omitempty"`
+	Nature     string         `json:"nature,omitempty"`
+	Friendship int            `json:"friendship"`
+	Moves      []string       `json:"moves"`
+	HeldItem   string         `json:"held_item,omitempty"`
+	CaughtAt   time.Time      `json:"caught_at"`
+	CaughtIn   string         `json:"caught_in,omitempty"`
+	History    []historyEntry `json:"history"`
+}
+
+func toSaved(c *caughtPokemon) savedPokemon {
+	return savedPokemon{
+		ID:         c.ID,
+		Pokemon:    c.Pokemon.Name,
+		Nickname:   c.Nickname,
+		Favorite:   c.Favorite,
+		Shiny:      c.Shiny,
+		Gender:     c.Gender,
+		Level:      c.Level,
+		Experience: c.Experience,
+		GrowthRate: c.GrowthRate,
+		IVs:        c.IVs,
+		EVs:        c.EVs,
+		Nature:     c.Nature,
+		Friendship: c.Friendship,
+		Moves:      c.Moves,
+		HeldItem:   c.HeldItem,
+		CaughtAt:   c.CaughtAt,
+		CaughtIn:   c.CaughtIn,
+		History:    c.History,
+	}
+}
+
+func (s savedPokemon) restore(pokemon Pokemon) *caughtPokemon {
+	return &caughtPokemon{
+		ID:         s.ID,
+		Nickname:   s.Nickname,
+		Favorite:   s.Favorite,
+		Shiny:      s.Shiny,
+		Gender:     s.Gender,
+		Level:      s.Level,
+		Experience: s.Experience,
+		GrowthRate: s.GrowthRate,
+		IVs:        s.IVs,
+		EVs:        s.EVs,
+		Nature:     s.Nature,
+		Friendship: s.Friendship,
+		Moves:      s.Moves,
+		HeldItem:   s.HeldItem,
+		CaughtAt:   s.CaughtAt,
+		CaughtIn:   s.CaughtIn,
+		Pokemon:    pokemon,
+		History:    s.History,
+	}
+}
+
+type profileItem struct {
+	Pocket string `json:"pocket"`
+	Count  int    `json:"count"`
+}
+
+// defaultProfilePath is where the profile lives unless told otherwise. An
+// empty path means there is nowhere to keep it.
+func defaultProfilePath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pokedexcli", "profile.json")
+}
+
+func saveProfile(conf *config) error {
+	if conf.profilePath == "" {
+		return fmt.Errorf("no profile location available")
+	}
+	p := profile{
+		NextID: conf.nextID,
+		Party:  conf.trainer.party,
+		Boxes:  conf.trainer.boxes,
+		Bag:    map[string]profileItem{},
+	}
+	for _, caught := range conf.pokedex {
+		p.Pokedex = append(p.Pokedex, toSaved(caught))
+	}
+	sort.Slice(p.Pokedex, func(i, j int) bool { return p.Pokedex[i].ID < p.Pokedex[j].ID })
+	for name, entry := range conf.trainer.bag {
+		p.Bag[name] = profileItem{Pocket: entry.pocket, Count: entry.count}
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("profile encoding failed: %s", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(conf.profilePath), 0o755); err != nil {
+		return fmt.Errorf("profile save failed: %s", err)
+	}
+	// Write next to the profile and rename so an interrupted save never
+	// leaves a truncated file behind.
+	tmp := conf.profilePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("profile save failed: %s", err)
+	}
+	if err := os.Rename(tmp, conf.profilePath); err != nil {
+		return fmt.Errorf("profile save failed: %s", err)
+	}
+	return nil
+}
+
+// loadProfile replaces the trainer state with the saved profile. A missing
+// profile leaves the fresh state in place and reports false.
+func loadProfile(conf *config) (bool, error) {
+	if conf.profilePath == "" {
+		return false, nil
+	}
+	data, err := os.ReadFile(conf.profilePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("profile load failed: %s", err)
+	}
+	var p profile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return false, fmt.Errorf("profile load failed: %s", err)
+	}
+
+	saved := map[int]savedPokemon{}
+	for _, s := range p.Pokedex {
+		saved[s.ID] = s
+	}
+	for _, id := range p.Party {
+		if _, ok := saved[id]; !ok {
+			return false, fmt.Errorf("profile load failed: party holds unknown pokemon #%d", id)
+		}
+	}
+	for _, b := range p.Boxes {
+		for _, id := range b {
+			if _, ok := saved[id]; id != 0 && !ok {
+				return false, fmt.Errorf("profile load failed: box holds unknown pokemon #%d", id)
+			}
+		}
+	}
+	pokedex := map[int]*caughtPokemon{}
+	for id, s := range saved {
+		pokemon, err := fetchPokemon(conf, s.Pokemon)
+		if err != nil {
+			return false, fmt.Errorf("profile load failed: %s", err)
+		}
+		pokedex[id] = s.restore(pokemon)
+	}
+	bag := map[string]*bagItem{}
+	for name, entry := range p.Bag {
+		bag[name] = &bagItem{pocket: entry.Pocket, count: entry.Count}
+	}
+
+	conf.pokedex = pokedex
+	conf.nextID = max(p.NextID, 1)
+	conf.trainer = &trainer{bag: bag, party: p.Party, boxes: p.Boxes}
+	return true, nil
+}
+
+// openProfile loads the profile at startup. When it can't be loaded the
+// session keeps its fresh state and stops saving, so exiting can't
+// overwrite the profile with it.
+func openProfile(conf *config) {
+	loaded, err := loadProfile(conf)
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("Your profile at " + conf.profilePath + " was left untouched and will not be saved this session")
+		conf.profilePath = ""
+		return
+	}
+	if loaded {
+		fmt.Println("Welcome back! Loaded " + strconv.Itoa(len(conf.pokedex)) + " pokemon from " + conf.profilePath)
+	}
+}
+
+// autosave saves the profile if there is somewhere to keep it.
+func autosave(conf *config) {
+	if conf.profilePath == "" {
+		return
+	}
+	if err := saveProfile(conf); err != nil {
+		fmt.Println(err)
+	}
+}
+
+func commandSave(conf *config, args ...string) error {
+	if err := saveProfile(conf); err != nil {
+		return err
+	}
+	fmt.Println("Profile saved to " + conf.profilePath)
+	return nil
+}
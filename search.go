@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jamistoso/pokedexcli/internal/pokeapi"
+)
+
+// The name index takes a dozen requests to build, so it is kept around far
+// longer than ordinary responses.
+const nameIndexTTL = time.Hour
+
+var indexedResources = []string{"pokemon", "location-area"}
+
+// loadNames returns every name of a resource list, building the index
+// from the full list on first use.
+func loadNames(conf *config, resource string) ([]string, error) {
+	key := pokeapi.BaseURL + resource + "?names"
+	if val, exists := conf.cache.Get(key); exists {
+		return strings.Split(string(val), "\n"), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	names := []string{}
+	for res, err := range pokeapi.ListAll(ctx, resource) {
+		if err != nil {
+			return nil, fmt.Errorf("building %s name index failed: %s", resource, err)
+		}
+		names = append(names, res.Name)
+	}
+	conf.cache.AddFor(key, []byte(strings.Join(names, "\n")), nameIndexTTL)
+	return names, nil
+}
+
+// levenshtein returns the edit distance between two strings.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// fuzzyMatches returns up to limit names close to term, best first. Names
+// containing the term rank ahead of names that are merely a few edits away.
+func fuzzyMatches(names []string, term string, limit int) []string {
+	// Substring matches are ranked by how much longer than the term they
+	// are, near misses by their edit distance.
+	type match struct {
+		name      string
+		substring bool
+		distance  int
+	}
+	maxDistance := max(2, len(term)/3)
+	matches := []match{}
+	for _, name := range names {
+		if strings.Contains(name, term) {
+			matches = append(matches, match{name: name, substring: true, distance: len(name) - len(term)})
+			continue
+		}
+		if d := levenshtein(name, term); d <= maxDistance {
+			matches = append(matches, match{name: name, distance: d})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].substring != matches[j].substring {
+			return matches[i].substring
+		}
+		return matches[i].distance < matches[j].distance
+	})
+
+	result := []string{}
+	for _, m := range matches {
+		if len(result) == limit {
+			break
+		}
+		result = append(result, m.name)
+	}
+	return result
+}
+
+// didYouMean returns a suggestion for a name that does not exist in the
+// resource, or an empty string when there is nothing better to offer.
+func didYouMean(conf *config, resource string, name string) string {
+	name = strings.ToLower(name)
+	names, err := loadNames(conf, resource)
+	if err != nil || slices.Contains(names, name) {
+		return ""
+	}
+	matches := fuzzyMatches(names, name, 3)
+	if len(matches) == 0 {
+		return ""
+	}
+	return " (did you mean " + strings.Join(matches, ", ") + "?)"
+}
+
+func commandSearch(conf *config, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: search <term>")
+	}
+	term := strings.ToLower(args[0])
+	found := false
+	for _, resource := range indexedResources {
+		names, err := loadNames(conf, resource)
+		if err != nil {
+			return err
+		}
+		matches := fuzzyMatches(names, term, 10)
+		if len(matches) == 0 {
+			continue
+		}
+		found = true
+		fmt.Println(resource + ":")
+		for _, name := range matches {
+			fmt.Println(" - " + name)
+		}
+	}
+	if !found {
+		fmt.Println("No matches for " + term)
+	}
+	return nil
+}
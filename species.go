@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jamistoso/pokedexcli/internal/pokeapi"
+)
+
+type pokemonSpecies struct {
+	ID             int                    `json:"id"`
+	Name           string                 `json:"name"`
+	CaptureRate    int                    `json:"capture_rate"`
+	BaseHappiness  int                    `json:"base_happiness"`
+	GenderRate     int                    `json:"gender_rate"`
+	IsLegendary    bool                   `json:"is_legendary"`
+	IsMythical     bool                   `json:"is_mythical"`
+	Color          pokeapi.NamedResource  `json:"color"`
+	Shape          *pokeapi.NamedResource `json:"shape"`
+	Habitat        *pokeapi.NamedResource `json:"habitat"`
+	GrowthRate     pokeapi.NamedResource  `json:"growth_rate"`
+	EvolutionChain struct {
+		URL string `json:"url"`
+	} `json:"evolution_chain"`
+	EggGroups []pokeapi.NamedResource `json:"egg_groups"`
+	Genera    []struct {
+		Genus    string                `json:"genus"`
+		Language pokeapi.NamedResource `json:"language"`
+	} `json:"genera"`
+	FlavorTextEntries []struct {
+		FlavorText string                `json:"flavor_text"`
+		Language   pokeapi.NamedResource `json:"language"`
+		Version    pokeapi.NamedResource `json:"version"`
+	} `json:"flavor_text_entries"`
+}
+
+func getPokemonSpecies(data []byte) (pokemonSpecies, error) {
+	var species pokemonSpecies
+	if err := json.Unmarshal(data, &species); err != nil {
+		return pokemonSpecies{}, err
+	}
+	return species, nil
+}
+
+// fetchSpecies resolves the species record for a pokemon through the cache.
+func fetchSpecies(conf *config, pokemon Pokemon) (pokemonSpecies, error) {
+	url := pokemon.Species.URL
+	if url == "" {
+		url = pokemonSpeciesURL + pokemon.Name
+	}
+	return fetchSpeciesURL(conf, url)
+}
+
+func fetchSpeciesURL(conf *config, url string) (pokemonSpecies, error) {
+	data, err := fetch(conf, url)
+	if err != nil {
+		return pokemonSpecies{}, err
+	}
+	species, err := getPokemonSpecies(data)
+	if err != nil {
+		return pokemonSpecies{}, fmt.Errorf("species retrieval failed: %s", err)
+	}
+	return species, nil
+}
+
+func (s pokemonSpecies) genus(language string) string {
+	for _, genus := range s.Genera {
+		if genus.Language.Name == language {
+			return genus.Genus
+		}
+	}
+	return ""
+}
+
+// flavorText picks the entry for the selected version, or the newest entry
+// in the language when no version is selected.
+func (s pokemonSpecies) flavorText(version string, language string) string {
+	text := ""
+	for _, entry := range s.FlavorTextEntries {
+		if entry.Language.Name != language || !inVersion(version, entry.Version.Name) {
+			continue
+		}
+		text = entry.FlavorText
+		if version != "" {
+			break
+		}
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// genderRatio describes gender_rate, which counts the chance of being
+// female in eighths, or -1 for genderless species.
+func genderRatio(rate int) string {
+	if rate < 0 {
+		return "genderless"
+	}
+	female := float64(rate) / 8 * 100
+	return strconv.FormatFloat(100-female, 'f', -1, 64) + "% male, " + strconv.FormatFloat(female, 'f', -1, 64) + "% female"
+}
+
+func nameOrUnknown(res *pokeapi.NamedResource) string {
+	if res == nil {
+		return "unknown"
+	}
+	return res.Name
+}
+
+func commandSpecies(conf *config, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: species <name>")
+	}
+	species, err := fetchSpeciesURL(conf, pokemonSpeciesURL+args[0])
+	if err != nil {
+		return err
+	}
+
+	eggGroups := []string{}
+	for _, group := range species.EggGroups {
+		eggGroups = append(eggGroups, group.Name)
+	}
+	fmt.Println(
+		"Name: "+species.Name,
+		"\nGenus: "+species.genus(conf.language),
+		"\nHabitat: "+nameOrUnknown(species.Habitat),
+		"\nColor: "+species.Color.Name,
+		"\nShape: "+nameOrUnknown(species.Shape),
+		"\nGender: "+genderRatio(species.GenderRate),
+		"\nEgg groups: "+strings.Join(eggGroups, ", "),
+		"\nCapture rate: "+strconv.Itoa(species.CaptureRate),
+		"\nBase happiness: "+strconv.Itoa(species.BaseHappiness),
+		"\nGrowth rate: "+species.GrowthRate.Name,
+		"\nLegendary: "+strconv.FormatBool(species.IsLegendary),
+		"\nMythical: "+strconv.FormatBool(species.IsMythical),
+	)
+	if text := species.flavorText(conf.version, conf.language); text != "" {
+		fmt.Println()
+		fmt.Println(text)
+	}
+	return nil
+}
+
+func commandLanguage(conf *config, args ...string) error {
+	if len(args) == 0 {
+		fmt.Println("Language: " + conf.language)
+		return nil
+	}
+	if _, err := fetch(conf, languageURL+args[0]); err != nil {
+		return fmt.Errorf("unknown language %s: %s", args[0], err)
+	}
+	conf.language = args[0]
+	fmt.Println("Language set to " + conf.language)
+	return nil
+}
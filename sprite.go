@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type colorMode int
+
+const (
+	colorASCII colorMode = iota
+	color256
+	colorTrueColor
+)
+
+// generationVersions picks the game whose sprites stand for a generation.
+var generationVersions = map[string]string{
+	"i":   "red",
+	"ii":  "crystal",
+	"iii": "ruby",
+	"iv":  "platinum",
+	"v":   "black",
+	"vi":  "x",
+	"vii": "ultra-sun",
+}
+
+// asciiRamp goes from the faintest to the densest character, so brighter
+// pixels print denser on a dark terminal.
+const asciiRamp = ".:-=+*#%@"
+
+const ansiReset = "\x1b[0m"
+
+func parseColorMode(name string) (colorMode, error) {
+	switch name {
+	case "truecolor":
+		return colorTrueColor, nil
+	case "256":
+		return color256, nil
+	case "ascii":
+		return colorASCII, nil
+	default:
+		return 0, fmt.Errorf("unknown color mode: %s", name)
+	}
+}
+
+// detectColorMode guesses what the terminal supports from its environment.
+func detectColorMode(getenv func(string) string) colorMode {
+	switch {
+	case getenv("COLORTERM") == "truecolor" || getenv("COLORTERM") == "24bit":
+		return colorTrueColor
+	case strings.Contains(getenv("TERM"), "256color"):
+		return color256
+	default:
+		return colorASCII
+	}
+}
+
+// opaque reports whether a pixel is solid enough to draw.
+func opaque(c color.NRGBA) bool {
+	return c.A >= 128
+}
+
+// spriteBounds is the smallest rectangle holding every opaque pixel, which
+// trims the empty margin sprites are padded with.
+func spriteBounds(img image.Image) image.Rectangle {
+	bounds := image.Rectangle{}
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			if opaque(pixel(img, x, y)) {
+				bounds = bounds.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return bounds
+}
+
+// pixel reads a pixel without premultiplied alpha. Pixels outside the
+// image are transparent.
+func pixel(img image.Image, x int, y int) color.NRGBA {
+	if !(image.Point{x, y}.In(img.Bounds())) {
+		return color.NRGBA{}
+	}
+	return color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+}
+
+// ansiColor is the escape sequence setting the foreground (or background)
+// to c in the given mode.
+func ansiColor(c color.NRGBA, mode colorMode, background bool) string {
+	layer := "38"
+	if background {
+		layer = "48"
+	}
+	if mode == colorTrueColor {
+		return "\x1b[" + layer + ";2;" + strconv.Itoa(int(c.R)) + ";" + strconv.Itoa(int(c.G)) + ";" + strconv.Itoa(int(c.B)) + "m"
+	}
+	return "\x1b[" + layer + ";5;" + strconv.Itoa(xterm256(c)) + "m"
+}
+
+// xterm256 maps a color onto the 6x6x6 cube of the 256 color palette.
+func xterm256(c color.NRGBA) int {
+	level := func(v uint8) int {
+		return (int(v)*5 + 127) / 255
+	}
+	return 16 + 36*level(c.R) + 6*level(c.G) + level(c.B)
+}
+
+func luminance(c color.NRGBA) float64 {
+	return (0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)) / 255
+}
+
+// renderSprite draws an image two pixel rows per line. Color modes use the
+// upper half block with the top pixel as foreground and the bottom one as
+// background; ASCII picks a character by the brightness of the pair.
+func renderSprite(img image.Image, mode colorMode) string {
+	bounds := spriteBounds(img)
+	var sb strings.Builder
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 2 {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			top, bottom := pixel(img, x, y), pixel(img, x, y+1)
+			if y+1 >= bounds.Max.Y {
+				bottom = color.NRGBA{}
+			}
+			if mode == colorASCII {
+				sb.WriteByte(asciiCell(top, bottom))
+				continue
+			}
+			switch {
+			case opaque(top) && opaque(bottom):
+				sb.WriteString(ansiColor(top, mode, false) + ansiColor(bottom, mode, true) + "▀")
+			case opaque(top):
+				sb.WriteString(ansiReset + ansiColor(top, mode, false) + "▀")
+			case opaque(bottom):
+				sb.WriteString(ansiReset + ansiColor(bottom, mode, false) + "▄")
+			default:
+				sb.WriteString(ansiReset + " ")
+			}
+		}
+		if mode != colorASCII {
+			sb.WriteString(ansiReset)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func asciiCell(top color.NRGBA, bottom color.NRGBA) byte {
+	total, n := 0.0, 0
+	for _, c := range []color.NRGBA{top, bottom} {
+		if opaque(c) {
+			total += luminance(c)
+			n++
+		}
+	}
+	if n == 0 {
+		return ' '
+	}
+	return asciiRamp[int(total/float64(n)*float64(len(asciiRamp)-1)+0.5)]
+}
+
+func commandSprite(conf *config, args ...string) error {
+	positional, flags := parseFlags(args, "shiny", "back")
+	if len(positional) == 0 {
+		return fmt.Errorf("usage: sprite <pokemon|id> [--shiny] [--back] [--gen i-vii] [--colors truecolor|256|ascii]")
+	}
+	_, shiny := flags["shiny"]
+	_, back := flags["back"]
+
+	// A caught pokemon is drawn as it was caught, anything else by species.
+	var pokemon Pokemon
+	female := false
+	if caught, err := findCaught(conf, positional[0]); err == nil {
+		pokemon = caught.Pokemon
+		shiny = shiny || caught.Shiny
+		female = caught.Gender == "female"
+	} else {
+		pokemon, err = fetchPokemon(conf, positional[0])
+		if err != nil {
+			return err
+		}
+	}
+
+	// An explicit generation must have the sprite; the selected game falls
+	// back to the default sprites.
+	var url string
+	if gen := flags["gen"]; gen != "" {
+		version, ok := generationVersions[strings.ToLower(gen)]
+		if !ok {
+			return fmt.Errorf("no sprites for generation %s", gen)
+		}
+		sprites, _ := versionSprites(pokemon, version)
+		url = pickSprite(sprites, shiny, female, back)
+		if url == "" {
+			return fmt.Errorf("%s has no such sprite in generation %s", pokemon.Name, gen)
+		}
+	} else {
+		url = variantSprite(pokemon, conf.version, shiny, female, back)
+		if url == "" {
+			return fmt.Errorf("%s has no such sprite", pokemon.Name)
+		}
+	}
+
+	mode := detectColorMode(os.Getenv)
+	if name := flags["colors"]; name != "" {
+		m, err := parseColorMode(name)
+		if err != nil {
+			return err
+		}
+		mode = m
+	}
+
+	data, err := fetch(conf, url)
+	if err != nil {
+		return err
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("sprite decoding failed: %s", err)
+	}
+	fmt.Print(renderSprite(img, mode))
+	return nil
+}
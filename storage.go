@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const partySize = 6
+const boxSize = 30
+
+// box holds caught pokemon ids by slot; 0 marks an empty slot.
+type box [boxSize]int
+
+// store puts a newly caught pokemon in the party if there is room, and in
+// the first free box slot otherwise.
+func (t *trainer) store(id int) {
+	if len(t.party) < partySize {
+		t.party = append(t.party, id)
+		return
+	}
+	b, s := t.freeSlot()
+	t.boxes[b][s] = id
+}
+
+// freeSlot finds the first empty box slot, opening a new box when every
+// box is full.
+func (t *trainer) freeSlot() (int, int) {
+	for b := range t.boxes {
+		for s, id := range t.boxes[b] {
+			if id == 0 {
+				return b, s
+			}
+		}
+	}
+	t.boxes = append(t.boxes, box{})
+	return len(t.boxes) - 1, 0
+}
+
+func (t *trainer) partyIndex(id int) int {
+	for i, member := range t.party {
+		if member == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *trainer) boxSlot(id int) (int, int, bool) {
+	for b := range t.boxes {
+		for s, boxed := range t.boxes[b] {
+			if boxed == id {
+				return b, s, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// unstore removes a pokemon from wherever it is kept.
+func (t *trainer) unstore(id int) {
+	if i := t.partyIndex(id); i >= 0 {
+		t.party = append(t.party[:i], t.party[i+1:]...)
+		return
+	}
+	if b, s, ok := t.boxSlot(id); ok {
+		t.boxes[b][s] = 0
+	}
+}
+
+// withdraw moves a boxed pokemon into the party.
+func (t *trainer) withdraw(id int) error {
+	b, s, ok := t.boxSlot(id)
+	if !ok {
+		return fmt.Errorf("#%d is not in a box", id)
+	}
+	if len(t.party) >= partySize {
+		return fmt.Errorf("your party is full, use party swap instead")
+	}
+	t.boxes[b][s] = 0
+	t.party = append(t.party, id)
+	return nil
+}
+
+// deposit moves a party member into the first free box slot.
+func (t *trainer) deposit(id int) error {
+	i := t.partyIndex(id)
+	if i < 0 {
+		return fmt.Errorf("#%d is not in your party", id)
+	}
+	if len(t.party) == 1 {
+		return fmt.Errorf("you can't deposit your last party member")
+	}
+	t.party = append(t.party[:i], t.party[i+1:]...)
+	b, s := t.freeSlot()
+	t.boxes[b][s] = id
+	return nil
+}
+
+// swap exchanges the places of two pokemon: two party members trade
+// positions, and a party member and a boxed pokemon trade places.
+func (t *trainer) swap(a, b int) error {
+	ia, ib := t.partyIndex(a), t.partyIndex(b)
+	switch {
+	case ia >= 0 && ib >= 0:
+		t.party[ia], t.party[ib] = t.party[ib], t.party[ia]
+		return nil
+	case ia >= 0 || ib >= 0:
+		if ib >= 0 {
+			a, b = b, a
+			ia = ib
+		}
+		boxNum, slot, ok := t.boxSlot(b)
+		if !ok {
+			return fmt.Errorf("#%d is neither in your party nor in a box", b)
+		}
+		t.party[ia] = b
+		t.boxes[boxNum][slot] = a
+		return nil
+	default:
+		return fmt.Errorf("at least one of #%d and #%d must be in your party", a, b)
+	}
+}
+
+func commandParty(conf *config, args ...string) error {
+	t := conf.trainer
+	if len(args) == 0 {
+		fmt.Println("Your party:")
+		for i, id := range t.party {
+			fmt.Println(" " + strconv.Itoa(i+1) + ". " + formatCaught(conf.pokedex[id]))
+		}
+		return nil
+	}
+
+	ids := []int{}
+	for _, arg := range args[1:] {
+		caught, err := findCaughtByID(conf, arg)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, caught.ID)
+	}
+	switch {
+	case args[0] == "add" && len(ids) == 1:
+		if err := t.withdraw(ids[0]); err != nil {
+			return err
+		}
+		fmt.Println(conf.pokedex[ids[0]].displayName() + " joined your party")
+	case args[0] == "remove" && len(ids) == 1:
+		if err := t.deposit(ids[0]); err != nil {
+			return err
+		}
+		b, _, _ := t.boxSlot(ids[0])
+		fmt.Println(conf.pokedex[ids[0]].displayName() + " was sent to box " + strconv.Itoa(b+1))
+	case args[0] == "swap" && len(ids) == 2:
+		if err := t.swap(ids[0], ids[1]); err != nil {
+			return err
+		}
+		fmt.Println("Swapped " + conf.pokedex[ids[0]].displayName() + " and " + conf.pokedex[ids[1]].displayName())
+	default:
+		return fmt.Errorf("usage: party [add <id>|remove <id>|swap <id> <id>]")
+	}
+	return nil
+}
+
+func commandBox(conf *config, args ...string) error {
+	t := conf.trainer
+	if len(args) == 0 {
+		for b := range t.boxes {
+			used := 0
+			for _, id := range t.boxes[b] {
+				if id != 0 {
+					used++
+				}
+			}
+			fmt.Println("Box " + strconv.Itoa(b+1) + ": " + strconv.Itoa(used) + "/" + strconv.Itoa(boxSize))
+		}
+		if len(t.boxes) == 0 {
+			fmt.Println("Your boxes are empty")
+		}
+		return nil
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 || n > len(t.boxes) {
+		return fmt.Errorf("there is no box %s", args[0])
+	}
+	fmt.Println("Box " + strconv.Itoa(n) + ":")
+	for s, id := range t.boxes[n-1] {
+		if id != 0 {
+			fmt.Println(" " + strconv.Itoa(s+1) + ". " + formatCaught(conf.pokedex[id]))
+		}
+	}
+	return nil
+}
+
+func storageName(t *trainer, id int) string {
+	if t.partyIndex(id) >= 0 {
+		return "your party"
+	}
+	if b, _, ok := t.boxSlot(id); ok {
+		return "box " + strconv.Itoa(b+1)
+	}
+	return "nowhere"
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+var statNames = []string{"hp", "attack", "defense", "special-attack", "special-defense", "speed"}
+
+func baseStats(pokemon Pokemon) map[string]int {
+	stats := map[string]int{}
+	for _, stat := range pokemon.Stats {
+		stats[stat.Stat.Name] = stat.BaseStat
+	}
+	return stats
+}
+
+// suggestRole gives a rough role for a pokemon from its base stats.
+func suggestRole(stats map[string]int) string {
+	attack, spAttack := stats["attack"], stats["special-attack"]
+	offense := max(attack, spAttack)
+	style := "physical"
+	if spAttack > attack {
+		style = "special"
+	}
+	switch {
+	case stats["speed"] >= 90 && offense >= 90:
+		return "fast " + style + " sweeper"
+	case offense >= 100:
+		return style + " wallbreaker"
+	case stats["defense"] >= 100 && stats["defense"] >= stats["special-defense"]:
+		return "physical wall"
+	case stats["special-defense"] >= 100:
+		return "special wall"
+	case stats["hp"] >= 90 && stats["defense"]+stats["special-defense"] >= 160:
+		return "tank"
+	default:
+		return "support"
+	}
+}
+
+// sharedWeaknesses returns the attacking types that hit at least two team
+// members super effectively, with the number of members they hit.
+func sharedWeaknesses(chart typeChart, team []*caughtPokemon) map[string]int {
+	shared := map[string]int{}
+	for _, attacker := range allTypes {
+		count := 0
+		for _, member := range team {
+			if chart.multiplier(attacker, pokemonTypes(member.Pokemon)) > 1 {
+				count++
+			}
+		}
+		if count >= 2 {
+			shared[attacker] = count
+		}
+	}
+	return shared
+}
+
+// uncoveredTypes returns the defending types none of the attacking types
+// hit super effectively.
+func uncoveredTypes(chart typeChart, attackingTypes []string) []string {
+	uncovered := []string{}
+	for _, defender := range allTypes {
+		covered := false
+		for _, attacker := range attackingTypes {
+			if chart.multiplier(attacker, []string{defender}) > 1 {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			uncovered = append(uncovered, defender)
+		}
+	}
+	return uncovered
+}
+
+// attackingTypes returns the types of the damaging moves a pokemon knows.
+func attackingTypes(conf *config, caught *caughtPokemon) ([]string, error) {
+	types := []string{}
+	for _, name := range knownMoves(caught, conf.versionGroup) {
+		m, err := fetchMove(conf, name)
+		if err != nil {
+			return nil, err
+		}
+		if m.isDamaging() && !slices.Contains(types, m.Type.Name) {
+			types = append(types, m.Type.Name)
+		}
+	}
+	return types, nil
+}
+
+func commandTeam(conf *config, args ...string) error {
+	if len(args) == 0 || args[0] != "analyze" {
+		return fmt.Errorf("usage: team analyze [<id>...]")
+	}
+	ids := args[1:]
+	if len(ids) == 0 {
+		for _, id := range conf.trainer.party {
+			ids = append(ids, strconv.Itoa(id))
+		}
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("your party is empty")
+	}
+	team := []*caughtPokemon{}
+	for _, arg := range ids {
+		caught, err := findCaughtByID(conf, arg)
+		if err != nil {
+			return err
+		}
+		team = append(team, caught)
+	}
+	return analyzeTeam(conf, team)
+}
+
+func analyzeTeam(conf *config, team []*caughtPokemon) error {
+	chart, err := loadTypeChart(conf)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Stats:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "POKEMON\tTYPES\tHP\tATK\tDEF\tSPA\tSPD\tSPE\tTOTAL\tROLE")
+	totals := map[string]int{}
+	teamTypes := []string{}
+	for _, member := range team {
+		stats := baseStats(member.Pokemon)
+		row := member.displayName() + "\t" + strings.Join(pokemonTypes(member.Pokemon), "/")
+		total := 0
+		for _, name := range statNames {
+			row += "\t" + strconv.Itoa(stats[name])
+			totals[name] += stats[name]
+			total += stats[name]
+		}
+		row += "\t" + strconv.Itoa(total) + "\t" + suggestRole(stats)
+		fmt.Fprintln(w, row)
+
+		types, err := attackingTypes(conf, member)
+		if err != nil {
+			return err
+		}
+		for _, t := range types {
+			if !slices.Contains(teamTypes, t) {
+				teamTypes = append(teamTypes, t)
+			}
+		}
+	}
+	row := "average\t"
+	total := 0
+	for _, name := range statNames {
+		row += "\t" + strconv.Itoa(totals[name]/len(team))
+		total += totals[name] / len(team)
+	}
+	fmt.Fprintln(w, row+"\t"+strconv.Itoa(total)+"\t")
+	w.Flush()
+
+	fmt.Println()
+	shared := sharedWeaknesses(chart, team)
+	if len(shared) == 0 {
+		fmt.Println("Shared weaknesses: none")
+	} else {
+		weak := []string{}
+		for attacker := range shared {
+			weak = append(weak, attacker)
+		}
+		sort.Slice(weak, func(i, j int) bool {
+			if shared[weak[i]] != shared[weak[j]] {
+				return shared[weak[i]] > shared[weak[j]]
+			}
+			return weak[i] < weak[j]
+		})
+		fmt.Println("Shared weaknesses:")
+		for _, attacker := range weak {
+			fmt.Println("	- " + attacker + ": " + strconv.Itoa(shared[attacker]) + " of " + strconv.Itoa(len(team)) + " members")
+		}
+	}
+
+	sort.Strings(teamTypes)
+	fmt.Println("Attacking move types: " + strings.Join(teamTypes, ", "))
+	uncovered := uncoveredTypes(chart, teamTypes)
+	if len(uncovered) == 0 {
+		fmt.Println("Every type is hit super effectively")
+	} else {
+		fmt.Println("No super effective coverage against: " + strings.Join(uncovered, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jamistoso/pokedexcli/internal/pokeapi"
+)
+
+// allTypes are the attacking types of the mainline games, in index order.
+var allTypes = []string{
+	"normal", "fighting", "flying", "poison", "ground", "rock", "bug", "ghost", "steel",
+	"fire", "water", "grass", "electric", "psychic", "ice", "dragon", "dark", "fairy",
+}
+
+type pokeType struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	DamageRelations struct {
+		DoubleDamageTo []pokeapi.NamedResource `json:"double_damage_to"`
+		HalfDamageTo   []pokeapi.NamedResource `json:"half_damage_to"`
+		NoDamageTo     []pokeapi.NamedResource `json:"no_damage_to"`
+	} `json:"damage_relations"`
+}
+
+// typeChart maps an attacking type to the multiplier it deals against each
+// defending type. Pairs that are missing deal normal damage.
+type typeChart map[string]map[string]float64
+
+func fetchType(conf *config, name string) (pokeType, error) {
+	data, err := fetch(conf, typeURL+name)
+	if err != nil {
+		return pokeType{}, err
+	}
+	var t pokeType
+	if err := json.Unmarshal(data, &t); err != nil {
+		return pokeType{}, fmt.Errorf("type retrieval failed: %s", err)
+	}
+	return t, nil
+}
+
+// loadTypeChart builds the chart from every type's damage relations. The
+// chart never changes, so it is built once per session.
+func loadTypeChart(conf *config) (typeChart, error) {
+	if conf.typeChart != nil {
+		return conf.typeChart, nil
+	}
+	chart := typeChart{}
+	for _, name := range allTypes {
+		t, err := fetchType(conf, name)
+		if err != nil {
+			return nil, err
+		}
+		row := map[string]float64{}
+		for _, defender := range t.DamageRelations.DoubleDamageTo {
+			row[defender.Name] = 2
+		}
+		for _, defender := range t.DamageRelations.HalfDamageTo {
+			row[defender.Name] = 0.5
+		}
+		for _, defender := range t.DamageRelations.NoDamageTo {
+			row[defender.Name] = 0
+		}
+		chart[name] = row
+	}
+	conf.typeChart = chart
+	return chart, nil
+}
+
+// multiplier returns the damage multiplier of an attacking type against a
+// pokemon with one or two defending types.
+func (c typeChart) multiplier(attacker string, defenders []string) float64 {
+	m := 1.0
+	for _, defender := range defenders {
+		if val, ok := c[attacker][defender]; ok {
+			m *= val
+		}
+	}
+	return m
+}
+
+// weaknesses groups every attacking type by its multiplier against the
+// defending types.
+func (c typeChart) weaknesses(defenders []string) map[float64][]string {
+	groups := map[float64][]string{}
+	for _, attacker := range allTypes {
+		m := c.multiplier(attacker, defenders)
+		groups[m] = append(groups[m], attacker)
+	}
+	return groups
+}
+
+func formatMultiplier(m float64) string {
+	switch m {
+	case 0.25:
+		return "1/4x"
+	case 0.5:
+		return "1/2x"
+	default:
+		return strconv.FormatFloat(m, 'f', -1, 64) + "x"
+	}
+}
+
+func describeEffectiveness(m float64) string {
+	switch {
+	case m == 0:
+		return "no effect"
+	case m > 1:
+		return "super effective"
+	case m < 1:
+		return "not very effective"
+	default:
+		return "normal damage"
+	}
+}
+
+func commandWeakness(conf *config, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: weakness <pokemon>")
+	}
+	pokemon, err := fetchPokemon(conf, args[0])
+	if err != nil {
+		return err
+	}
+	chart, err := loadTypeChart(conf)
+	if err != nil {
+		return err
+	}
+	types := pokemonTypes(pokemon)
+	groups := chart.weaknesses(types)
+
+	fmt.Println(pokemon.Name + " (" + strings.Join(types, "/") + ")")
+	for _, m := range []float64{4, 2, 0.5, 0.25, 0} {
+		if len(groups[m]) == 0 {
+			continue
+		}
+		fmt.Println("	" + formatMultiplier(m) + ": " + strings.Join(groups[m], ", "))
+	}
+	return nil
+}
+
+func commandMatchup(conf *config, args ...string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: matchup <attacker-type> <pokemon>")
+	}
+	attacker := args[0]
+	if !slices.Contains(allTypes, attacker) {
+		return fmt.Errorf("unknown type: %s", attacker)
+	}
+	pokemon, err := fetchPokemon(conf, args[1])
+	if err != nil {
+		return err
+	}
+	chart, err := loadTypeChart(conf)
+	if err != nil {
+		return err
+	}
+	types := pokemonTypes(pokemon)
+	m := chart.multiplier(attacker, types)
+	fmt.Println(attacker + " vs " + pokemon.Name + " (" + strings.Join(types, "/") + "): " + formatMultiplier(m) + " (" + describeEffectiveness(m) + ")")
+	return nil
+}
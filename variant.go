@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+)
+
+// defaultShinyOdds is the one-in-n chance of a shiny from generation VI on.
+const defaultShinyOdds = 4096
+
+// rollShiny decides whether a catch is shiny with a one-in-odds chance.
+// Odds of zero or less never give a shiny.
+func rollShiny(rng *rand.Rand, odds int) bool {
+	return odds > 0 && rng.Intn(odds) == 0
+}
+
+// rollGender picks a gender from the species gender rate, the chance of a
+// female in eighths, or -1 for genderless species.
+func rollGender(rng *rand.Rand, rate int) string {
+	switch {
+	case rate < 0:
+		return "genderless"
+	case rng.Intn(8) < rate:
+		return "female"
+	default:
+		return "male"
+	}
+}
+
+// pickSprite returns the sprite matching a pokemon's variant, falling back
+// to the plain sprite when the game has no shiny or female version of it.
+func pickSprite(sprites spriteSet, shiny bool, female bool, back bool) string {
+	plain, shinyOnly, femaleOnly, both := sprites.FrontDefault, sprites.FrontShiny, sprites.FrontFemale, sprites.FrontShinyFemale
+	if back {
+		plain, shinyOnly, femaleOnly, both = sprites.BackDefault, sprites.BackShiny, sprites.BackFemale, sprites.BackShinyFemale
+	}
+	candidates := []string{}
+	if shiny && female {
+		candidates = append(candidates, both)
+	}
+	if shiny {
+		candidates = append(candidates, shinyOnly)
+	}
+	if female {
+		candidates = append(candidates, femaleOnly)
+	}
+	for _, sprite := range append(candidates, plain) {
+		if sprite != "" {
+			return sprite
+		}
+	}
+	return ""
+}
+
+// variantSprite picks the matching sprite from the game's set, falling
+// back to the current default sprites for games the API has no set for or
+// whose set lacks that view.
+func variantSprite(pokemon Pokemon, version string, shiny bool, female bool, back bool) string {
+	if sprites, ok := versionSprites(pokemon, version); ok {
+		if sprite := pickSprite(sprites, shiny, female, back); sprite != "" {
+			return sprite
+		}
+	}
+	sprites, _ := versionSprites(pokemon, "")
+	return pickSprite(sprites, shiny, female, back)
+}
+
+func printSprite(caught *caughtPokemon, version string) {
+	if sprite := variantSprite(caught.Pokemon, version, caught.Shiny, caught.Gender == "female", false); sprite != "" {
+		fmt.Println("Sprite: " + sprite)
+	}
+}
+
+func commandShinyOdds(conf *config, args ...string) error {
+	if len(args) == 0 {
+		fmt.Println("Shiny odds: 1 in " + strconv.Itoa(conf.shinyOdds))
+		return nil
+	}
+	odds, err := strconv.Atoi(args[0])
+	if err != nil || odds < 1 {
+		return fmt.Errorf("invalid odds: %s", args[0])
+	}
+	conf.shinyOdds = odds
+	fmt.Println("Shiny odds set to 1 in " + strconv.Itoa(odds))
+	return nil
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+type gameVersion struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	VersionGroup struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"version_group"`
+}
+
+// spriteSet is one game's set of sprite URLs. Missing sprites are empty.
+type spriteSet struct {
+	FrontDefault     string
+	FrontShiny       string
+	FrontFemale      string
+	FrontShinyFemale string
+	BackDefault      string
+	BackShiny        string
+	BackFemale       string
+	BackShinyFemale  string
+}
+
+func getGameVersion(data []byte) (gameVersion, error) {
+	var version gameVersion
+	if err := json.Unmarshal(data, &version); err != nil {
+		return gameVersion{}, err
+	}
+	return version, nil
+}
+
+func commandVersion(conf *config, args ...string) error {
+	if len(args) == 0 {
+		if conf.version == "" {
+			fmt.Println("No game version selected, showing data from every game")
+		} else {
+			fmt.Println("Game version: " + conf.version + " (" + conf.versionGroup + ")")
+		}
+		return nil
+	}
+	if args[0] == "all" {
+		conf.version = ""
+		conf.versionGroup = ""
+		fmt.Println("Showing data from every game")
+		return nil
+	}
+
+	data, err := fetch(conf, versionURL+args[0])
+	if err != nil {
+		return err
+	}
+	version, err := getGameVersion(data)
+	if err != nil {
+		return fmt.Errorf("version retrieval failed: %s", err)
+	}
+	conf.version = version.Name
+	conf.versionGroup = version.VersionGroup.Name
+	setEncounter(conf, nil)
+	fmt.Println("Game version set to " + version.Name + " (" + version.VersionGroup.Name + ")")
+	return nil
+}
+
+// inVersion reports whether data tagged with name belongs to the selected
+// version. An empty selection matches everything.
+func inVersion(selected string, name string) bool {
+	return selected == "" || selected == name
+}
+
+// gameIndex returns the pokemon's index number in the given game.
+func gameIndex(pokemon Pokemon, version string) (int, bool) {
+	for _, index := range pokemon.GameIndices {
+		if index.Version.Name == version {
+			return index.GameIndex, true
+		}
+	}
+	return 0, false
+}
+
+// versionMoves returns the names of the moves the pokemon can learn in a
+// version group.
+func versionMoves(pokemon Pokemon, versionGroup string) []string {
+	moves := []string{}
+	for _, move := range pokemon.Moves {
+		for _, detail := range move.VersionGroupDetails {
+			if inVersion(versionGroup, detail.VersionGroup.Name) {
+				moves = append(moves, move.Move.Name)
+				break
+			}
+		}
+	}
+	return moves
+}
+
+func anyString(val any) string {
+	if str, ok := val.(string); ok {
+		return str
+	}
+	return ""
+}
+
+// versionSprites returns the sprites a pokemon had in the given game,
+// falling back to the current default sprites when no game is selected.
+// Games the API has no sprites for report false.
+func versionSprites(pokemon Pokemon, version string) (spriteSet, bool) {
+	s := pokemon.Sprites
+	v := s.Versions
+	switch version {
+	case "":
+		return spriteSet{
+			FrontDefault:     s.FrontDefault,
+			FrontShiny:       s.FrontShiny,
+			FrontFemale:      anyString(s.FrontFemale),
+			FrontShinyFemale: anyString(s.FrontShinyFemale),
+			BackDefault:      s.BackDefault,
+			BackShiny:        s.BackShiny,
+			BackFemale:       anyString(s.BackFemale),
+			BackShinyFemale:  anyString(s.BackShinyFemale),
+		}, true
+	case "red", "blue":
+		g := v.GenerationI.RedBlue
+		return spriteSet{FrontDefault: g.FrontDefault, BackDefault: g.BackDefault}, true
+	case "yellow":
+		g := v.GenerationI.Yellow
+		return spriteSet{FrontDefault: g.FrontDefault, BackDefault: g.BackDefault}, true
+	case "gold":
+		g := v.GenerationIi.Gold
+		return spriteSet{FrontDefault: g.FrontDefault, FrontShiny: g.FrontShiny, BackDefault: g.BackDefault, BackShiny: g.BackShiny}, true
+	case "silver":
+		g := v.GenerationIi.Silver
+		return spriteSet{FrontDefault: g.FrontDefault, FrontShiny: g.FrontShiny, BackDefault: g.BackDefault, BackShiny: g.BackShiny}, true
+	case "crystal":
+		g := v.GenerationIi.Crystal
+		return spriteSet{FrontDefault: g.FrontDefault, FrontShiny: g.FrontShiny, BackDefault: g.BackDefault, BackShiny: g.BackShiny}, true
+	case "ruby", "sapphire":
+		g := v.GenerationIii.RubySapphire
+		return spriteSet{FrontDefault: g.FrontDefault, FrontShiny: g.FrontShiny, BackDefault: g.BackDefault, BackShiny: g.BackShiny}, true
+	case "emerald":
+		g := v.GenerationIii.Emerald
+		return spriteSet{FrontDefault: g.FrontDefault, FrontShiny: g.FrontShiny}, true
+	case "firered", "leafgreen":
+		g := v.GenerationIii.FireredLeafgreen
+		return spriteSet{FrontDefault: g.FrontDefault, FrontShiny: g.FrontShiny, BackDefault: g.BackDefault, BackShiny: g.BackShiny}, true
+	case "diamond", "pearl":
+		g := v.GenerationIv.DiamondPearl
+		return spriteSet{
+			FrontDefault:     g.FrontDefault,
+			FrontShiny:       g.FrontShiny,
+			FrontFemale:      anyString(g.FrontFemale),
+			FrontShinyFemale: anyString(g.FrontShinyFemale),
+			BackDefault:      g.BackDefault,
+			BackShiny:        g.BackShiny,
+			BackFemale:       anyString(g.BackFemale),
+			BackShinyFemale:  anyString(g.BackShinyFemale),
+		}, true
+	case "platinum":
+		g := v.GenerationIv.Platinum
+		return spriteSet{
+			FrontDefault:     g.FrontDefault,
+			FrontShiny:       g.FrontShiny,
+			FrontFemale:      anyString(g.FrontFemale),
+			FrontShinyFemale: anyString(g.FrontShinyFemale),
+			BackDefault:      g.BackDefault,
+			BackShiny:        g.BackShiny,
+			BackFemale:       anyString(g.BackFemale),
+			BackShinyFemale:  anyString(g.BackShinyFemale),
+		}, true
+	case "heartgold", "soulsilver":
+		g := v.GenerationIv.HeartgoldSoulsilver
+		return spriteSet{
+			FrontDefault:     g.FrontDefault,
+			FrontShiny:       g.FrontShiny,
+			FrontFemale:      anyString(g.FrontFemale),
+			FrontShinyFemale: anyString(g.FrontShinyFemale),
+			BackDefault:      g.BackDefault,
+			BackShiny:        g.BackShiny,
+			BackFemale:       anyString(g.BackFemale),
+			BackShinyFemale:  anyString(g.BackShinyFemale),
+		}, true
+	case "black", "white", "black-2", "white-2":
+		g := v.GenerationV.BlackWhite
+		return spriteSet{
+			FrontDefault:     g.FrontDefault,
+			FrontShiny:       g.FrontShiny,
+			FrontFemale:      anyString(g.FrontFemale),
+			FrontShinyFemale: anyString(g.FrontShinyFemale),
+			BackDefault:      g.BackDefault,
+			BackShiny:        g.BackShiny,
+			BackFemale:       anyString(g.BackFemale),
+			BackShinyFemale:  anyString(g.BackShinyFemale),
+		}, true
+	case "x", "y":
+		g := v.GenerationVi.XY
+		return spriteSet{
+			FrontDefault:     g.FrontDefault,
+			FrontShiny:       g.FrontShiny,
+			FrontFemale:      anyString(g.FrontFemale),
+			FrontShinyFemale: anyString(g.FrontShinyFemale),
+		}, true
+	case "omega-ruby", "alpha-sapphire":
+		g := v.GenerationVi.OmegarubyAlphasapphire
+		return spriteSet{
+			FrontDefault:     g.FrontDefault,
+			FrontShiny:       g.FrontShiny,
+			FrontFemale:      anyString(g.FrontFemale),
+			FrontShinyFemale: anyString(g.FrontShinyFemale),
+		}, true
+	case "sun", "moon", "ultra-sun", "ultra-moon":
+		g := v.GenerationVii.UltraSunUltraMoon
+		return spriteSet{
+			FrontDefault:     g.FrontDefault,
+			FrontShiny:       g.FrontShiny,
+			FrontFemale:      anyString(g.FrontFemale),
+			FrontShinyFemale: anyString(g.FrontShinyFemale),
+		}, true
+	default:
+		return spriteSet{}, false
+	}
+}
+
+func printVersionDetails(pokemon Pokemon, version string, versionGroup string) {
+	fmt.Println("In " + version + ":")
+	if index, ok := gameIndex(pokemon, version); ok {
+		fmt.Println("	-game index: " + strconv.Itoa(index))
+	} else {
+		fmt.Println("	-not available in this game")
+	}
+	fmt.Println("	-learnable moves: " + strconv.Itoa(len(versionMoves(pokemon, versionGroup))))
+}